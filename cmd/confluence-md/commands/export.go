@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin/attachments"
+	"github.com/jackchuka/confluence-md/internal/importer"
+	"github.com/jackchuka/confluence-md/internal/importer/htmlexport"
+	"github.com/jackchuka/confluence-md/internal/importer/xmlexport"
+	"github.com/spf13/cobra"
+)
+
+// ExportOptions contains all options for the export command
+type ExportOptions struct {
+	commonOptions
+	linkOptions
+	jiraOptions
+	todoOptions
+
+	OutputNamer converter.OutputNamer
+	Converter   []converter.Option
+	Tasks       *converter.TaskReport
+
+	SpaceKey string // Space key recorded in frontmatter, default: export directory name
+	BaseURL  string // Confluence base URL used for page links in frontmatter
+}
+
+var exportOpts ExportOptions
+
+// exportCmd represents the export command for offline space exports
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Convert a Confluence HTML or XML space export offline",
+	Long: `Convert every page of a Confluence space export to Markdown.
+
+The export can be the downloaded zip file or its extracted directory. No API
+access is required, and referenced attachments are copied from the export.
+
+HTML exports are converted from their rendered view markup, with the page
+hierarchy rebuilt from index.html. XML backups (containing entities.xml) are
+converted from the latest storage format body of each page, with the hierarchy
+taken from the page parent relationships.
+
+Examples:
+  # Convert a zipped export
+  confluence-md export ~/Downloads/Confluence-space-export.zip
+
+  # Convert an XML space backup
+  confluence-md export ~/Downloads/Confluence-space-export-xml.zip
+
+  # Convert an extracted export and record the original site in frontmatter
+  confluence-md export ./SPACE --base-url https://example.atlassian.net --output ./docs`,
+	RunE: runExportCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportOpts.commonOptions.InitFlags(exportCmd)
+	exportOpts.linkOptions.InitFlags(exportCmd)
+	exportOpts.jiraOptions.InitFlags(exportCmd)
+	exportOpts.todoOptions.InitFlags(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportOpts.SpaceKey, "space-key", "", "Space key for converted pages (default: export directory name)")
+	exportCmd.Flags().StringVar(&exportOpts.BaseURL, "base-url", "", "Confluence base URL used for page links in frontmatter")
+}
+
+func runExportCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: export zip or directory")
+	}
+
+	namer, err := buildOutputNamer(exportOpts.OutputNameTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid output name template: %w", err)
+	}
+	exportOpts.OutputNamer = namer
+
+	exportOpts.Converter, err = exportOpts.NewConverterOptions()
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	exportOpts.Tasks, err = exportOpts.NewTaskReport()
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	links, err := exportOpts.linkOptions.NewLinkIndex(exportOpts.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	dir, cleanup, err := importer.Unpack(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+	defer func() {
+		_ = cleanup()
+	}()
+
+	export, err := loadExport(dir, exportOpts.SpaceKey)
+	if err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+
+	return performExportConversion(export, &exportOpts, links)
+}
+
+// loadExport reads an XML backup when the export contains entities.xml, and an HTML export otherwise
+func loadExport(dir, spaceKey string) (*importer.Export, error) {
+	if _, err := importer.FindFile(dir, xmlexport.EntitiesFile); err == nil {
+		return xmlexport.Load(dir, spaceKey)
+	}
+	return htmlexport.Load(dir, spaceKey)
+}
+
+func performExportConversion(export *importer.Export, opts *ExportOptions, links *converter.LinkIndex) error {
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	jiraConfig, err := opts.JiraConfig(opts.BaseURL, "", "")
+	if err != nil {
+		return fmt.Errorf("invalid Jira options: %w", err)
+	}
+
+	conversionOpts := PageOptions{
+		commonOptions: opts.commonOptions,
+		OutputNamer:   opts.OutputNamer,
+		Converter:     opts.Converter,
+		Attachments:   attachments.NewLocalService(export.Root),
+		Jira:          jiraConfig,
+	}
+
+	client := export.Client()
+	results := &ConversionResults{}
+	_ = export.Walk(func(node *importer.Node, path []string) error {
+		page := node.Page
+		fmt.Printf("📄 Converting: %s\n", page.Title)
+
+		// Pages used only to group their children have no body to convert
+		if strings.TrimSpace(page.Content.Storage.Value) == "" {
+			fmt.Printf("  ⏭️  Skipped empty page\n\n")
+			results.Skipped++
+			return nil
+		}
+
+		outputPath, err := getOutputPath(&PageNode{ID: page.ID, Title: page.Title, Path: path}, page, opts.OutputDir, opts.OutputNamer)
+		if err != nil {
+			fmt.Printf("  ❌ Failed to resolve output path: %v\n", err)
+			results.Failed++
+			results.Errors = append(results.Errors, err)
+			return nil
+		}
+
+		result := convertSinglePageWithPath(client, page, opts.BaseURL, outputPath, conversionOpts)
+		printConversionResult(result)
+
+		if result.Success {
+			results.Success++
+			links.Add(page.ID, result.OutputPath)
+			if opts.Tasks != nil {
+				opts.Tasks.Add(page.ID, page.Title, result.OutputPath, result.Tasks)
+			}
+		} else {
+			results.Failed++
+			results.Errors = append(results.Errors, result.Error)
+		}
+		return nil
+	})
+	rewritePageLinks(links)
+
+	fmt.Printf("✅ Conversion complete!\n")
+	fmt.Printf("  Successful: %d pages\n", results.Success)
+	if results.Skipped > 0 {
+		fmt.Printf("  Skipped: %d empty pages\n", results.Skipped)
+	}
+	if results.Failed > 0 {
+		fmt.Printf("  Failed: %d pages\n", results.Failed)
+		fmt.Printf("  See error details above\n")
+	}
+	fmt.Printf("  Output: %s\n", opts.OutputDir)
+	opts.WriteTaskReport(opts.Tasks, opts.OutputDir)
+
+	if results.Failed > 0 {
+		return fmt.Errorf("conversion completed with errors")
+	}
+
+	return nil
+}
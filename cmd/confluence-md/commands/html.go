@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/jackchuka/confluence-md/internal/confluence"
 	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/adf"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +19,8 @@ var htmlCmd = &cobra.Command{
 
 Read HTML from a file or stdin and convert it to Markdown.
 This is useful for testing or converting exported HTML content.
+Atlassian Document Format (ADF) JSON is accepted as well and is
+detected automatically unless --body-format is given.
 
 Examples:
   # Convert from file
@@ -29,7 +33,10 @@ Examples:
   confluence-md html page.html -o output.md
 
   # Convert from stdin and save
-  cat page.html | confluence-md html -o output.md`,
+  cat page.html | confluence-md html -o output.md
+
+  # Convert an ADF JSON body
+  confluence-md html page.json --body-format adf`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runHTMLConvert,
 }
@@ -37,11 +44,13 @@ Examples:
 var htmlOptions struct {
 	output      string
 	imageFolder string
+	bodyFormat  string
 }
 
 func init() {
 	htmlCmd.Flags().StringVarP(&htmlOptions.output, "output", "o", "", "Output file (default: stdout)")
 	htmlCmd.Flags().StringVar(&htmlOptions.imageFolder, "image-folder", "assets", "Folder path for images in markdown")
+	htmlCmd.Flags().StringVar(&htmlOptions.bodyFormat, "body-format", "", "Input format (storage or adf; default: detect)")
 
 	rootCmd.AddCommand(htmlCmd)
 }
@@ -73,10 +82,25 @@ func runHTMLConvert(cmd *cobra.Command, args []string) error {
 	// Create converter (using nil client for HTML-only conversion)
 	conv := converter.NewConverter(nil, converter.WithDownloadAttachments(htmlOptions.imageFolder))
 
-	// Convert HTML to Markdown
-	markdown, err := conv.ConvertHTML(string(htmlContent))
+	bodyFormat := confluence.BodyFormatStorage
+	if htmlOptions.bodyFormat != "" {
+		bodyFormat, err = confluence.ParseBodyFormat(htmlOptions.bodyFormat)
+		if err != nil {
+			return err
+		}
+	} else if adf.IsDocument(string(htmlContent)) {
+		bodyFormat = confluence.BodyFormatADF
+	}
+
+	// Convert HTML (or ADF) to Markdown
+	var markdown string
+	if bodyFormat == confluence.BodyFormatADF {
+		markdown, err = conv.ConvertADF(string(htmlContent))
+	} else {
+		markdown, err = conv.ConvertHTML(string(htmlContent))
+	}
 	if err != nil {
-		return fmt.Errorf("failed to convert HTML: %w", err)
+		return fmt.Errorf("failed to convert %s input: %w", bodyFormat, err)
 	}
 
 	// Write output
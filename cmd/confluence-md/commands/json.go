@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin/attachments"
+	"github.com/jackchuka/confluence-md/internal/importer"
+	"github.com/jackchuka/confluence-md/internal/importer/apijson"
+	"github.com/spf13/cobra"
+)
+
+// JSONOptions contains all options for the json command
+type JSONOptions struct {
+	commonOptions
+	linkOptions
+	jiraOptions
+
+	OutputNamer converter.OutputNamer
+	Converter   []converter.Option
+
+	AttachmentsDir string // Directory holding downloaded attachments, default: beside the input
+	BaseURL        string // Confluence base URL, default: the response's _links.base
+}
+
+var jsonOpts JSONOptions
+
+// jsonCmd represents the json command for saved REST API responses
+var jsonCmd = &cobra.Command{
+	Use:   "json [file-or-directory]",
+	Short: "Convert saved Confluence REST API page responses to Markdown",
+	Long: `Convert Confluence page responses saved from the REST API to Markdown.
+
+Accepts a single JSON file or a directory of them (searched recursively). Each
+response should include the page body, and may include the version, history,
+metadata.labels and children.attachment expansions, which are used for the
+frontmatter and attachments. No API access is required.
+
+Attachments are read from --attachments, looking for each attachment's download
+path (e.g. download/attachments/<page-id>/<file>), then <page-id>/<file>, then
+<file>.
+
+Examples:
+  # Convert a saved response
+  confluence-md json page.json
+
+  # Convert a directory of responses with attachments downloaded alongside
+  confluence-md json ./responses --attachments ./responses/files --output ./docs`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJSONCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(jsonCmd)
+
+	jsonOpts.commonOptions.InitFlags(jsonCmd)
+	jsonOpts.linkOptions.InitFlags(jsonCmd)
+	jsonOpts.jiraOptions.InitFlags(jsonCmd)
+
+	jsonCmd.Flags().StringVar(&jsonOpts.AttachmentsDir, "attachments", "", "Directory containing attachment files (default: the input's directory)")
+	jsonCmd.Flags().StringVar(&jsonOpts.BaseURL, "base-url", "", "Confluence base URL used for page links (default: from the response)")
+}
+
+func runJSONCommand(_ *cobra.Command, args []string) error {
+	input := args[0]
+
+	namer, err := buildOutputNamer(jsonOpts.OutputNameTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid output name template: %w", err)
+	}
+	jsonOpts.OutputNamer = namer
+
+	jsonOpts.Converter, err = jsonOpts.NewConverterOptions()
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	docs, err := apijson.Load(input)
+	if err != nil {
+		return err
+	}
+
+	baseURL := jsonOpts.BaseURL
+	if baseURL == "" {
+		baseURL = docs[0].BaseURL
+	}
+	links, err := jsonOpts.linkOptions.NewLinkIndex(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	jiraConfig, err := jsonOpts.JiraConfig(baseURL, "", "")
+	if err != nil {
+		return fmt.Errorf("invalid Jira options: %w", err)
+	}
+
+	attachmentsDir := jsonOpts.AttachmentsDir
+	if attachmentsDir == "" {
+		attachmentsDir = input
+		if info, err := os.Stat(input); err == nil && !info.IsDir() {
+			attachmentsDir = filepath.Dir(input)
+		}
+	}
+
+	if err := os.MkdirAll(jsonOpts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Links between the loaded pages are resolved from the responses themselves
+	export := &importer.Export{Root: attachmentsDir}
+	for _, doc := range docs {
+		export.Pages = append(export.Pages, &importer.Node{Page: doc.Page})
+	}
+	client := export.Client()
+
+	conversionOpts := PageOptions{
+		commonOptions: jsonOpts.commonOptions,
+		OutputNamer:   jsonOpts.OutputNamer,
+		Converter:     jsonOpts.Converter,
+		Attachments:   attachments.NewLocalService(attachmentsDir),
+		Jira:          jiraConfig,
+	}
+
+	results := &ConversionResults{}
+	for _, doc := range docs {
+		pageBaseURL := jsonOpts.BaseURL
+		if pageBaseURL == "" {
+			pageBaseURL = doc.BaseURL
+		}
+
+		result := convertSinglePage(client, doc.Page, pageBaseURL, conversionOpts)
+		printConversionResult(result)
+
+		if result.Success {
+			results.Success++
+			links.Add(doc.Page.ID, result.OutputPath)
+		} else {
+			results.Failed++
+			results.Errors = append(results.Errors, result.Error)
+		}
+	}
+
+	rewritePageLinks(links)
+
+	if len(docs) > 1 {
+		fmt.Printf("✅ Conversion complete!\n")
+		fmt.Printf("  Successful: %d pages\n", results.Success)
+		if results.Failed > 0 {
+			fmt.Printf("  Failed: %d pages\n", results.Failed)
+		}
+		fmt.Printf("  Output: %s\n", jsonOpts.OutputDir)
+	}
+
+	if results.Failed > 0 {
+		return fmt.Errorf("conversion completed with errors")
+	}
+	return nil
+}
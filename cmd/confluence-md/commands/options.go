@@ -1,17 +1,135 @@
 package commands
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/callout"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"github.com/jackchuka/confluence-md/internal/jira"
 	"github.com/spf13/cobra"
 )
 
 type authOptions struct {
-	APIKey string
-	Email  string
+	APIKey     string
+	Email      string
+	APIVersion string
+	BodyFormat string
 }
 
 func (a *authOptions) InitFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&a.APIKey, "api-token", "t", "", "Confluence API token (required)")
 	cmd.Flags().StringVarP(&a.Email, "email", "e", "", "Confluence user email (default: extracted from URL)")
+	cmd.Flags().StringVar(&a.APIVersion, "api-version", "v1", "Confluence REST API version to use (v1 or v2; v2 falls back to v1 on failure)")
+	cmd.Flags().StringVar(&a.BodyFormat, "body-format", "storage", "Page body to convert (storage or adf)")
+}
+
+// NewClient creates a caching Confluence client for the selected API version and body format
+func (a *authOptions) NewClient(baseURL string) (confluence.Client, error) {
+	bodyFormat, err := confluence.ParseBodyFormat(a.BodyFormat)
+	if err != nil {
+		return nil, err
+	}
+	client, err := confluence.NewClientForVersion(baseURL, a.Email, a.APIKey, confluence.APIVersion(a.APIVersion), confluence.WithBodyFormat(bodyFormat))
+	if err != nil {
+		return nil, err
+	}
+	return confluence.NewCachingClient(client), nil
+}
+
+type linkOptions struct {
+	LinkPolicy string
+}
+
+func (l *linkOptions) InitFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&l.LinkPolicy, "link-policy", string(converter.LinkPolicyURL), "How to write links to pages that were not converted (url, confluence or text)")
+}
+
+// NewLinkIndex creates the index used to rewrite links between converted pages
+func (l *linkOptions) NewLinkIndex(baseURL string) (*converter.LinkIndex, error) {
+	policy, err := converter.ParseLinkPolicy(l.LinkPolicy)
+	if err != nil {
+		return nil, err
+	}
+	return converter.NewLinkIndex(baseURL, policy), nil
+}
+
+type todoOptions struct {
+	TodoReport  string
+	TodoGroupBy string
+}
+
+func (t *todoOptions) InitFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&t.TodoReport, "todo-report", "", "Collect every task into a report written to the output directory as <name>.md and <name>.json (tasks of included pages are not repeated for the including page)")
+	cmd.Flags().StringVar(&t.TodoGroupBy, "todo-group-by", string(converter.TaskGroupAssignee), "How the Markdown task report is sectioned (assignee or page)")
+}
+
+// NewTaskReport creates the task report, or nil when none was requested
+func (t *todoOptions) NewTaskReport() (*converter.TaskReport, error) {
+	if t.TodoReport == "" {
+		return nil, nil
+	}
+	grouping, err := converter.ParseTaskGrouping(t.TodoGroupBy)
+	if err != nil {
+		return nil, err
+	}
+	return converter.NewTaskReport(grouping), nil
+}
+
+// WriteTaskReport writes the report to the output directory
+func (t *todoOptions) WriteTaskReport(report *converter.TaskReport, outputDir string) {
+	if report == nil {
+		return
+	}
+	base := filepath.Join(outputDir, strings.TrimSuffix(t.TodoReport, filepath.Ext(t.TodoReport)))
+	if err := report.Write(base+".md", base+".json"); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to write task report: %v\n", err)
+		return
+	}
+	fmt.Printf("  Tasks: %s.md\n", base)
+}
+
+type jiraOptions struct {
+	JiraURL     string
+	JiraServers map[string]string
+	JiraIssues  string
+	JiraEnrich  bool
+}
+
+func (j *jiraOptions) InitFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&j.JiraURL, "jira-url", "", "Jira base URL for jira macros (default: the Confluence site)")
+	cmd.Flags().StringToStringVar(&j.JiraServers, "jira-server", nil, "Jira base URL for a jira macro server ID (serverId=url, repeatable)")
+	cmd.Flags().StringVar(&j.JiraIssues, "jira-issues", "", "Saved Jira search response (JSON) used to add issue summaries and statuses offline")
+	cmd.Flags().BoolVar(&j.JiraEnrich, "jira-enrich", false, "Fetch issue summaries and statuses from Jira using the Confluence credentials")
+}
+
+// JiraConfig builds the Jira settings for conversion. Live enrichment uses the given
+// credentials against the Jira URL, or the Confluence site when none is set.
+func (j *jiraOptions) JiraConfig(confluenceURL, email, apiToken string) (jira.Config, error) {
+	cfg := jira.Config{BaseURL: j.JiraURL, Servers: j.JiraServers}
+
+	switch {
+	case j.JiraIssues != "":
+		provider, err := jira.LoadIssues(j.JiraIssues)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Provider = provider
+	case j.JiraEnrich:
+		if apiToken == "" {
+			return cfg, fmt.Errorf("--jira-enrich requires API credentials; use --jira-issues for offline conversion")
+		}
+		site := j.JiraURL
+		if site == "" {
+			site = confluenceURL
+		}
+		cfg.Provider = jira.NewClient(site, email, apiToken)
+	}
+	return cfg, nil
 }
 
 type commonOptions struct {
@@ -20,6 +138,16 @@ type commonOptions struct {
 	IncludeMetadata    bool
 	OutputDir          string
 	OutputNameTemplate string
+	HeadingIDs         string
+	CalloutStyle       string
+	CalloutLabels      map[string]string
+	ExpandDetails      bool
+	TOCPlaceholder     bool
+	DiagramMacros      map[string]string
+	MathStyle          string
+	CodeTitles         string
+	Layout             string
+	EmbedMedia         bool
 }
 
 func (c *commonOptions) InitFlags(cmd *cobra.Command) {
@@ -28,4 +156,61 @@ func (c *commonOptions) InitFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&c.IncludeMetadata, "include-metadata", true, "Include YAML frontmatter")
 	cmd.Flags().StringVarP(&c.OutputDir, "output", "o", "./output", "Output directory")
 	cmd.Flags().StringVar(&c.OutputNameTemplate, "output-name-template", "", "Go template for output filename; available data: {{ .Page.* }}, {{ .SlugTitle }}, {{ .LabelNames }}")
+	cmd.Flags().StringVar(&c.CalloutStyle, "callout-style", string(callout.StyleBlockquote), "Syntax for info/warning/note/tip macros, panels and expands (blockquote, gfm, mkdocs, docusaurus or obsidian)")
+	cmd.Flags().StringToStringVar(&c.CalloutLabels, "callout-label", nil, "Callout label override, e.g. info=Hinweis (kinds: info, note, warning, tip, success, error, panel, expand)")
+	cmd.Flags().BoolVar(&c.ExpandDetails, "expand-details", false, "Render expand macros as collapsible <details> blocks with their titles")
+	cmd.Flags().BoolVar(&c.TOCPlaceholder, "toc-placeholder", false, "Write toc macros as a <!-- Table of Contents --> comment instead of a generated list")
+	cmd.Flags().StringToStringVar(&c.DiagramMacros, "diagram-macro", nil, "Write a diagram macro's source as a code block, as macro=language, e.g. kroki=plantuml (an empty language disables a default)")
+	cmd.Flags().StringVar(&c.MathStyle, "math-style", string(plugin.MathDollar), "Delimiters for math macros (dollar for $...$, latex for \\(...\\), or gfm for $`...`$ and math fences)")
+	cmd.Flags().StringVar(&c.CodeTitles, "code-titles", string(plugin.CodeTitlesCaption), "How code block titles are written (caption for a bold line above the block, or info for title=\"...\" in the fence info string)")
+	cmd.Flags().StringVar(&c.Layout, "layout", string(layout.StyleLinear), "How page layout and section macro columns are written (linear, separated for <!-- column --> comments between columns, or grid for an HTML flex row)")
+	cmd.Flags().BoolVar(&c.EmbedMedia, "embed-media", false, "Embed multimedia and widget macros (YouTube, Loom, Vimeo, video files) as <video> and <iframe> elements instead of links")
+	cmd.Flags().StringVar(&c.HeadingIDs, "heading-ids", string(plugin.HeadingIDsConfluence), "Heading and anchor ID scheme (confluence for #PageTitle-Heading, or github)")
+}
+
+// NewCallouts creates the callout renderer for the selected style, labels and expand format
+func (c *commonOptions) NewCallouts() (*callout.Renderer, error) {
+	style, err := callout.ParseStyle(c.CalloutStyle)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := callout.ParseLabels(c.CalloutLabels)
+	if err != nil {
+		return nil, err
+	}
+	r := callout.New(style, labels)
+	r.SetExpandDetails(c.ExpandDetails)
+	return r, nil
+}
+
+// NewConverterOptions validates the conversion flags once so that an invalid value
+// is rejected before any page is fetched
+func (c *commonOptions) NewConverterOptions() ([]converter.Option, error) {
+	headingIDs, err := plugin.ParseHeadingIDStyle(c.HeadingIDs)
+	if err != nil {
+		return nil, err
+	}
+	mathStyle, err := plugin.ParseMathStyle(c.MathStyle)
+	if err != nil {
+		return nil, err
+	}
+	codeTitles, err := plugin.ParseCodeTitleStyle(c.CodeTitles)
+	if err != nil {
+		return nil, err
+	}
+	layoutStyle, err := layout.ParseStyle(c.Layout)
+	if err != nil {
+		return nil, err
+	}
+	callouts, err := c.NewCallouts()
+	if err != nil {
+		return nil, err
+	}
+	return []converter.Option{
+		converter.WithHeadingIDs(headingIDs),
+		converter.WithCallouts(callouts),
+		converter.WithMathStyle(mathStyle),
+		converter.WithCodeTitles(codeTitles),
+		converter.WithLayoutStyle(layoutStyle),
+	}, nil
 }
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/jackchuka/confluence-md/internal/confluence"
 	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin/attachments"
+	"github.com/jackchuka/confluence-md/internal/jira"
 	"github.com/spf13/cobra"
 )
 
@@ -38,8 +39,13 @@ var pageOpts PageOptions
 type PageOptions struct {
 	authOptions
 	commonOptions
+	linkOptions
+	jiraOptions
 
 	OutputNamer converter.OutputNamer
+	Converter   []converter.Option   // Conversion options parsed from the flags
+	Attachments attachments.Resolver // Overrides the client as the attachment source
+	Jira        jira.Config
 }
 
 func init() {
@@ -47,6 +53,8 @@ func init() {
 
 	pageOpts.authOptions.InitFlags(pageCmd)
 	pageOpts.commonOptions.InitFlags(pageCmd)
+	pageOpts.linkOptions.InitFlags(pageCmd)
+	pageOpts.jiraOptions.InitFlags(pageCmd)
 
 	// Required flags
 	_ = pageCmd.MarkFlagRequired("api-token")
@@ -72,8 +80,26 @@ func runPage(_ *cobra.Command, args []string) error {
 	}
 	pageOpts.OutputNamer = namer
 
+	pageOpts.Converter, err = pageOpts.NewConverterOptions()
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	links, err := pageOpts.linkOptions.NewLinkIndex(pageInfo.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	pageOpts.Jira, err = pageOpts.JiraConfig(pageInfo.BaseURL, pageOpts.Email, pageOpts.APIKey)
+	if err != nil {
+		return fmt.Errorf("invalid Jira options: %w", err)
+	}
+
 	// Create Confluence client
-	client := confluence.NewClient(pageInfo.BaseURL, pageOpts.Email, pageOpts.APIKey)
+	client, err := pageOpts.authOptions.NewClient(pageInfo.BaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to create Confluence client: %w", err)
+	}
 
 	page, err := client.GetPage(pageInfo.PageID)
 	if err != nil {
@@ -93,6 +119,11 @@ func runPage(_ *cobra.Command, args []string) error {
 		pageOpts,
 	)
 
+	if result.Success {
+		links.Add(page.ID, result.OutputPath)
+		rewritePageLinks(links)
+	}
+
 	// Print results
 	printConversionResult(result)
 
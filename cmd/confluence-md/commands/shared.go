@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/gosimple/slug"
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
 	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/model"
 )
 
 // sanitizeFileName uses the mature gosimple/slug library for robust filename sanitization
@@ -46,6 +48,7 @@ type PageConversionResult struct {
 	PageID      string
 	Title       string
 	ImagesCount int
+	Tasks       []model.Task
 	Success     bool
 	Error       error
 }
@@ -73,10 +76,18 @@ func convertSinglePageWithPath(client confluence.Client, page *confluenceModel.C
 	result.OutputPath = outputPath
 
 	// Create converter and convert page
-	var options []converter.Option
+	options := append(slices.Clip(opts.Converter),
+		converter.WithTOCPlaceholder(opts.TOCPlaceholder),
+		converter.WithTextDiagrams(opts.DiagramMacros),
+		converter.WithMediaEmbeds(opts.EmbedMedia),
+	)
 	if opts.DownloadImages {
 		options = append(options, converter.WithDownloadAttachments(opts.ImageFolder))
 	}
+	if opts.Attachments != nil {
+		options = append(options, converter.WithAttachmentResolver(opts.Attachments))
+	}
+	options = append(options, converter.WithJira(opts.Jira))
 	conv := converter.NewConverter(client, options...)
 	doc, err := conv.ConvertPage(page, baseURL, filepath.Dir(outputPath))
 	if err != nil {
@@ -84,6 +95,7 @@ func convertSinglePageWithPath(client confluence.Client, page *confluenceModel.C
 		return result
 	}
 	result.ImagesCount = len(doc.Images)
+	result.Tasks = doc.Tasks
 
 	if err := converter.SaveMarkdownDocument(doc, outputPath, opts.IncludeMetadata); err != nil {
 		result.Error = fmt.Errorf("failed to save document: %w", err)
@@ -94,6 +106,13 @@ func convertSinglePageWithPath(client confluence.Client, page *confluenceModel.C
 	return result
 }
 
+// rewritePageLinks rewrites links between the converted pages to relative paths
+func rewritePageLinks(links *converter.LinkIndex) {
+	if err := links.RewriteFiles(); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to rewrite page links: %v\n", err)
+	}
+}
+
 // printConversionResult prints the result of a page conversion in a consistent format
 func printConversionResult(result *PageConversionResult) {
 	if result.Success {
@@ -9,6 +9,7 @@ import (
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
 	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/jira"
 	"github.com/spf13/cobra"
 )
 
@@ -16,8 +17,14 @@ import (
 type TreeOptions struct {
 	authOptions
 	commonOptions
+	linkOptions
+	jiraOptions
+	todoOptions
 
 	OutputNamer converter.OutputNamer
+	Converter   []converter.Option
+	Jira        jira.Config
+	Tasks       *converter.TaskReport
 
 	// Processing options
 	MaxDepth int      // -1 for unlimited, default: 3
@@ -55,6 +62,9 @@ func init() {
 
 	treeOpts.authOptions.InitFlags(treeCmd)
 	treeOpts.commonOptions.InitFlags(treeCmd)
+	treeOpts.linkOptions.InitFlags(treeCmd)
+	treeOpts.jiraOptions.InitFlags(treeCmd)
+	treeOpts.todoOptions.InitFlags(treeCmd)
 
 	// Required flags
 	_ = treeCmd.MarkFlagRequired("api-token")
@@ -85,20 +95,43 @@ func runTreeCommand(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid options: %w", err)
 	}
 
+	links, err := treeOpts.linkOptions.NewLinkIndex(pageInfo.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
 	namer, err := buildOutputNamer(treeOpts.OutputNameTemplate)
 	if err != nil {
 		return fmt.Errorf("invalid output name template: %w", err)
 	}
 	treeOpts.OutputNamer = namer
 
-	client := confluence.NewClient(pageInfo.BaseURL, treeOpts.Email, treeOpts.APIKey)
+	treeOpts.Converter, err = treeOpts.NewConverterOptions()
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	treeOpts.Tasks, err = treeOpts.NewTaskReport()
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	treeOpts.Jira, err = treeOpts.JiraConfig(pageInfo.BaseURL, treeOpts.Email, treeOpts.APIKey)
+	if err != nil {
+		return fmt.Errorf("invalid Jira options: %w", err)
+	}
+
+	client, err := treeOpts.authOptions.NewClient(pageInfo.BaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to create Confluence client: %w", err)
+	}
 
 	if treeOpts.DryRun {
 		fmt.Println("🔍 Dry run mode - analyzing page tree...")
 		return performDryRun(client, pageInfo.PageID, &treeOpts)
 	}
 
-	return performTreeConversion(client, pageInfo.BaseURL, pageInfo.PageID, &treeOpts)
+	return performTreeConversion(client, pageInfo.BaseURL, pageInfo.PageID, &treeOpts, links)
 }
 
 func validateTreeOptions() error {
@@ -137,7 +170,7 @@ func performDryRun(client confluence.Client, rootPageID string, opts *TreeOption
 	return nil
 }
 
-func performTreeConversion(client confluence.Client, baseURL, rootPageID string, opts *TreeOptions) error {
+func performTreeConversion(client confluence.Client, baseURL, rootPageID string, opts *TreeOptions, links *converter.LinkIndex) error {
 	// Create output directory
 	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -151,7 +184,8 @@ func performTreeConversion(client confluence.Client, baseURL, rootPageID string,
 
 	// Convert tree recursively using shared pipeline
 	results := &ConversionResults{}
-	err = convertPageTree(client, tree, opts.OutputDir, baseURL, opts, results)
+	err = convertPageTree(client, tree, opts.OutputDir, baseURL, opts, results, links)
+	rewritePageLinks(links)
 
 	// Display results
 	fmt.Printf("✅ Conversion complete!\n")
@@ -161,6 +195,7 @@ func performTreeConversion(client confluence.Client, baseURL, rootPageID string,
 		fmt.Printf("  See error details above\n")
 	}
 	fmt.Printf("  Output: %s\n", opts.OutputDir)
+	opts.WriteTaskReport(opts.Tasks, opts.OutputDir)
 
 	if err != nil {
 		return fmt.Errorf("conversion completed with errors")
@@ -191,6 +226,7 @@ type TreeStats struct {
 type ConversionResults struct {
 	Success int
 	Failed  int
+	Skipped int
 	Errors  []error
 }
 
@@ -310,7 +346,7 @@ func calculateTreeStats(node *PageNode) *TreeStats {
 	return stats
 }
 
-func convertPageTree(client confluence.Client, node *PageNode, outputDir string, baseURL string, opts *TreeOptions, results *ConversionResults) error {
+func convertPageTree(client confluence.Client, node *PageNode, outputDir string, baseURL string, opts *TreeOptions, results *ConversionResults, links *converter.LinkIndex) error {
 	if node == nil {
 		return nil
 	}
@@ -337,9 +373,11 @@ func convertPageTree(client confluence.Client, node *PageNode, outputDir string,
 
 	// Create options for tree conversion (inherit from tree options)
 	conversionOpts := PageOptions{
-		authOptions:   authOptions{Email: opts.Email, APIKey: opts.APIKey},
+		authOptions:   opts.authOptions,
 		commonOptions: opts.commonOptions,
 		OutputNamer:   opts.OutputNamer,
+		Converter:     opts.Converter,
+		Jira:          opts.Jira,
 	}
 
 	// Use shared conversion pipeline with custom path
@@ -350,6 +388,10 @@ func convertPageTree(client confluence.Client, node *PageNode, outputDir string,
 
 	if result.Success {
 		results.Success++
+		links.Add(page.ID, result.OutputPath)
+		if opts.Tasks != nil {
+			opts.Tasks.Add(page.ID, page.Title, result.OutputPath, result.Tasks)
+		}
 	} else {
 		results.Failed++
 		results.Errors = append(results.Errors, result.Error)
@@ -357,7 +399,7 @@ func convertPageTree(client confluence.Client, node *PageNode, outputDir string,
 
 	// Convert children
 	for _, child := range node.Children {
-		if err := convertPageTree(client, child, outputDir, baseURL, opts, results); err != nil {
+		if err := convertPageTree(client, child, outputDir, baseURL, opts, results, links); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,91 @@
+package confluence
+
+import (
+	"sync"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// cachingClient memoizes lookups, so pages, users and link targets referenced
+// many times during a conversion are only fetched once
+type cachingClient struct {
+	Client
+
+	mu       sync.Mutex
+	pages    map[string]*model.ConfluencePage
+	children map[string][]*model.ConfluencePage
+	users    map[string]*model.ConfluenceUser
+	content  map[model.ContentRef]*model.ConfluencePage
+	searches map[searchKey][]*model.ConfluencePage
+}
+
+// searchKey identifies a CQL query and its result limit
+type searchKey struct {
+	cql   string
+	limit int
+}
+
+// NewCachingClient wraps a client with an in-memory cache of successful responses.
+// Attachment downloads are not cached.
+func NewCachingClient(client Client) Client {
+	return &cachingClient{
+		Client:   client,
+		pages:    make(map[string]*model.ConfluencePage),
+		children: make(map[string][]*model.ConfluencePage),
+		users:    make(map[string]*model.ConfluenceUser),
+		content:  make(map[model.ContentRef]*model.ConfluencePage),
+		searches: make(map[searchKey][]*model.ConfluencePage),
+	}
+}
+
+func (c *cachingClient) GetPage(pageID string) (*model.ConfluencePage, error) {
+	return cached(c, c.pages, pageID, func() (*model.ConfluencePage, error) {
+		return c.Client.GetPage(pageID)
+	})
+}
+
+func (c *cachingClient) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
+	return cached(c, c.children, pageID, func() ([]*model.ConfluencePage, error) {
+		return c.Client.GetChildPages(pageID)
+	})
+}
+
+func (c *cachingClient) GetUser(accountID string) (*model.ConfluenceUser, error) {
+	return cached(c, c.users, accountID, func() (*model.ConfluenceUser, error) {
+		return c.Client.GetUser(accountID)
+	})
+}
+
+// FindContent caches misses as well, since unresolved links tend to repeat
+func (c *cachingClient) FindContent(ref model.ContentRef) (*model.ConfluencePage, error) {
+	return cached(c, c.content, ref, func() (*model.ConfluencePage, error) {
+		return c.Client.FindContent(ref)
+	})
+}
+
+// SearchContent caches results, so listing macros repeated across pages run their query once
+func (c *cachingClient) SearchContent(cql string, limit int) ([]*model.ConfluencePage, error) {
+	return cached(c, c.searches, searchKey{cql: cql, limit: limit}, func() ([]*model.ConfluencePage, error) {
+		return c.Client.SearchContent(cql, limit)
+	})
+}
+
+// cached returns the entry for key, calling fetch and storing its result on a miss
+func cached[K comparable, V any](c *cachingClient, entries map[K]V, key K, fetch func() (V, error)) (V, error) {
+	c.mu.Lock()
+	value, ok := entries[key]
+	c.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	entries[key] = value
+	c.mu.Unlock()
+	return value, nil
+}
@@ -0,0 +1,123 @@
+package confluence
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestClientFindContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/wiki/rest/api/content" && r.URL.Query().Get("title") == "Runbook":
+			if r.URL.Query().Get("spaceKey") != "OPS" || r.URL.Query().Get("type") != "page" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"results":[{"id":"42","title":"Runbook","space":{"key":"OPS"}}],"size":1}`))
+		case r.URL.Path == "/wiki/rest/api/content":
+			_, _ = w.Write([]byte(`{"results":[],"size":0}`))
+		case r.URL.Path == "/wiki/rest/api/space/OPS":
+			_, _ = w.Write([]byte(`{"id":1,"key":"OPS","homepage":{"id":"7","title":"Operations"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"statusCode":404,"message":"not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, "user@example.com", "token")
+
+	page, err := client.FindContent(model.ContentRef{Type: model.ContentTypePage, SpaceKey: "OPS", Title: "Runbook"})
+	if err != nil || page == nil || page.ID != "42" || page.SpaceKey != "OPS" {
+		t.Fatalf("unexpected page lookup: %+v, %v", page, err)
+	}
+
+	page, err = client.FindContent(model.ContentRef{Type: model.ContentTypePage, SpaceKey: "OPS", Title: "Missing"})
+	if err != nil || page != nil {
+		t.Fatalf("expected no match, got %+v, %v", page, err)
+	}
+
+	home, err := client.FindContent(model.ContentRef{Type: model.ContentTypeSpace, SpaceKey: "OPS"})
+	if err != nil || home == nil || home.ID != "7" || home.Title != "Operations" {
+		t.Fatalf("unexpected space home page: %+v, %v", home, err)
+	}
+
+	if home, err := client.FindContent(model.ContentRef{Type: model.ContentTypeSpace, SpaceKey: "GONE"}); err != nil || home != nil {
+		t.Fatalf("expected missing space to resolve to nothing, got %+v, %v", home, err)
+	}
+}
+
+func TestClientSearchContent(t *testing.T) {
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wiki/rest/api/content/search" || r.URL.Query().Get("cql") != `label = "ops" order by title asc` {
+			t.Errorf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		starts = append(starts, r.URL.Query().Get("start"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("start") == "0" {
+			_, _ = w.Write([]byte(`{"results":[{"id":"1","title":"Alerts"},{"id":"2","title":"Backups"}],"start":0,"limit":2,"size":2}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"id":"3","title":"Capacity"},{"id":"4","title":"Deploys"}],"start":2,"limit":2,"size":2}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, "user@example.com", "token")
+	pages, err := client.SearchContent(`label = "ops" order by title asc`, 3)
+	if err != nil {
+		t.Fatalf("SearchContent returned error: %v", err)
+	}
+	var titles []string
+	for _, page := range pages {
+		titles = append(titles, page.Title)
+	}
+	if strings.Join(titles, ",") != "Alerts,Backups,Capacity" {
+		t.Fatalf("unexpected results: %v", titles)
+	}
+	if strings.Join(starts, ",") != "0,2" {
+		t.Fatalf("unexpected pagination: %v", starts)
+	}
+}
+
+func TestCachingClient(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wiki/rest/api/content/1":
+			_, _ = w.Write([]byte(`{"id":"1","title":"Home","space":{"key":"DOCS"},"body":{"storage":{"value":"<p>x</p>"}}}`))
+		case "/wiki/rest/api/content":
+			_, _ = w.Write([]byte(`{"results":[],"size":0}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"statusCode":500,"message":"boom"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewCachingClient(NewClient(server.URL, "user@example.com", "token"))
+	ref := model.ContentRef{Type: model.ContentTypePage, SpaceKey: "DOCS", Title: "Missing"}
+	for range 2 {
+		if _, err := client.GetPage("1"); err != nil {
+			t.Fatalf("GetPage returned error: %v", err)
+		}
+		if page, err := client.FindContent(ref); err != nil || page != nil {
+			t.Fatalf("unexpected lookup: %+v, %v", page, err)
+		}
+		if _, err := client.GetUser("broken"); err == nil {
+			t.Fatal("expected error from GetUser")
+		}
+	}
+
+	// Errors are not cached, so only the failing user lookup is repeated
+	if got := requests.Load(); got != 4 {
+		t.Fatalf("expected 4 requests, got %d", got)
+	}
+}
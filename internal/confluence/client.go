@@ -20,6 +20,30 @@ type Client interface {
 	GetChildPages(pageID string) ([]*model.ConfluencePage, error)
 	DownloadAttachmentContent(attachment *model.ConfluenceAttachment) ([]byte, error)
 	GetUser(accountID string) (*model.ConfluenceUser, error)
+	// FindContent looks up a page, blog post or space home page. It returns nil without an error when nothing matches.
+	FindContent(ref model.ContentRef) (*model.ConfluencePage, error)
+	// SearchContent returns up to limit pages and blog posts matching a CQL query, in the query's order.
+	SearchContent(cql string, limit int) ([]*model.ConfluencePage, error)
+}
+
+// BodyFormat selects which body representation is requested for pages
+type BodyFormat string
+
+const (
+	BodyFormatStorage BodyFormat = "storage"
+	BodyFormatADF     BodyFormat = "atlas_doc_format"
+)
+
+// ParseBodyFormat maps a user-facing body format name to a BodyFormat
+func ParseBodyFormat(name string) (BodyFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "storage":
+		return BodyFormatStorage, nil
+	case "adf", string(BodyFormatADF):
+		return BodyFormatADF, nil
+	default:
+		return "", fmt.Errorf("unsupported body format %q (expected adf or storage)", name)
+	}
 }
 
 // client represents a Confluence API client
@@ -27,21 +51,43 @@ type client struct {
 	baseURL    string
 	email      string
 	apiToken   string
+	bodyFormat BodyFormat
 	httpClient *http.Client
 	userAgent  string
 }
 
+// ClientOption configures a Confluence API client
+type ClientOption func(*client)
+
+// WithBodyFormat selects the body representation fetched for pages
+func WithBodyFormat(format BodyFormat) ClientOption {
+	return func(c *client) {
+		if format != "" {
+			c.bodyFormat = format
+		}
+	}
+}
+
 // NewClient creates a new Confluence API client
-func NewClient(baseURL, email, apiToken string) Client {
-	return &client{
-		baseURL:  strings.TrimSuffix(baseURL, "/"),
-		email:    email,
-		apiToken: apiToken,
+func NewClient(baseURL, email, apiToken string, opts ...ClientOption) Client {
+	c := &client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		email:      email,
+		apiToken:   apiToken,
+		bodyFormat: BodyFormatStorage,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 		userAgent: fmt.Sprintf("ConfluenceMd/%s", version.Short()),
 	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	return c
 }
 
 // GetPage retrieves a Confluence page by ID
@@ -50,7 +96,7 @@ func (c *client) GetPage(pageID string) (*model.ConfluencePage, error) {
 	endpoint := fmt.Sprintf("/wiki/rest/api/content/%s", pageID)
 	params := url.Values{
 		"expand": []string{
-			"body.storage,metadata.labels,version,space,history,children.attachment",
+			fmt.Sprintf("body.%s,metadata.labels,version,space,history,children.attachment", c.bodyFormat),
 		},
 	}
 
@@ -85,7 +131,7 @@ const defaultChildPageLimit = 100
 func (c *client) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
 	endpoint := fmt.Sprintf("/wiki/rest/api/content/%s/child/page", pageID)
 	params := url.Values{
-		"expand": []string{"body.storage,metadata.labels,version,space,history"},
+		"expand": []string{fmt.Sprintf("body.%s,metadata.labels,version,space,history", c.bodyFormat)},
 		"limit":  []string{strconv.Itoa(defaultChildPageLimit)},
 	}
 
@@ -313,6 +359,126 @@ func (c *client) GetUser(accountID string) (*model.ConfluenceUser, error) {
 	return &user, nil
 }
 
+// FindContent looks up linked content by space and title
+func (c *client) FindContent(ref model.ContentRef) (*model.ConfluencePage, error) {
+	if ref.Type == model.ContentTypeSpace {
+		return c.getSpaceHomePage(ref.SpaceKey)
+	}
+
+	params := url.Values{
+		"type":     []string{ref.Type},
+		"spaceKey": []string{ref.SpaceKey},
+		"title":    []string{ref.Title},
+		"expand":   []string{"space,version"},
+		"limit":    []string{"1"},
+	}
+	if ref.PostingDay != "" {
+		params.Set("postingDay", ref.PostingDay)
+	}
+	fullURL := c.baseURL + "/wiki/rest/api/content?" + params.Encode()
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s %q: %w", ref.Type, ref.Title, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("find %s %q", ref.Type, ref.Title))
+	}
+
+	var searchResult model.ConfluenceSearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&searchResult); err != nil {
+		return nil, fmt.Errorf("failed to decode content search response: %w", err)
+	}
+	if len(searchResult.Results) == 0 {
+		return nil, nil
+	}
+
+	return model.ConvertAPIPageToModel(&searchResult.Results[0]), nil
+}
+
+// SearchContent runs a CQL query, following pagination until limit results are collected
+func (c *client) SearchContent(cql string, limit int) ([]*model.ConfluencePage, error) {
+	params := url.Values{
+		"cql":    []string{cql},
+		"expand": []string{"space,version,history"},
+	}
+
+	var pages []*model.ConfluencePage
+	for len(pages) < limit {
+		params.Set("start", strconv.Itoa(len(pages)))
+		params.Set("limit", strconv.Itoa(min(limit-len(pages), defaultChildPageLimit)))
+		fullURL := c.baseURL + "/wiki/rest/api/content/search?" + params.Encode()
+
+		resp, err := c.makeRequest("GET", fullURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search content: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := c.handleErrorResponse(resp, fmt.Sprintf("search content with %q", cql))
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		var searchResult model.ConfluenceSearchResult
+		if err := json.NewDecoder(resp.Body).Decode(&searchResult); err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode content search response: %w", err)
+		}
+		_ = resp.Body.Close()
+
+		for _, apiPage := range searchResult.Results {
+			pages = append(pages, model.ConvertAPIPageToModel(&apiPage))
+		}
+		if len(searchResult.Results) == 0 || searchResult.Size < searchResult.Limit {
+			break
+		}
+	}
+
+	if len(pages) > limit {
+		pages = pages[:limit]
+	}
+	return pages, nil
+}
+
+// getSpaceHomePage returns a summary of a space's home page
+func (c *client) getSpaceHomePage(spaceKey string) (*model.ConfluencePage, error) {
+	fullURL := fmt.Sprintf("%s/wiki/rest/api/space/%s?expand=homepage", c.baseURL, url.PathEscape(spaceKey))
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get space %s: %w", spaceKey, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("get space %s", spaceKey))
+	}
+
+	var space model.ConfluenceAPISpace
+	if err := json.NewDecoder(resp.Body).Decode(&space); err != nil {
+		return nil, fmt.Errorf("failed to decode space response: %w", err)
+	}
+	if space.Homepage.ID == "" {
+		return nil, nil
+	}
+
+	return &model.ConfluencePage{
+		ID:       space.Homepage.ID,
+		Title:    space.Homepage.Title,
+		SpaceKey: space.Key,
+	}, nil
+}
+
 // handleErrorResponse handles error responses from the API
 func (c *client) handleErrorResponse(resp *http.Response, operation string) error {
 	bodyBytes, err := io.ReadAll(resp.Body)
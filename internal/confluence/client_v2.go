@@ -0,0 +1,184 @@
+package confluence
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+const defaultV2PageLimit = 250
+
+// clientV2 implements Client against the Confluence Cloud REST API v2.
+// Attachment downloads and user lookups have no v2 equivalent and are
+// served by the embedded v1 client.
+type clientV2 struct {
+	*client
+	spaceKeys map[string]string // spaceID -> key
+}
+
+// NewClientV2 creates a new Confluence API client backed by the v2 REST API
+func NewClientV2(baseURL, email, apiToken string, opts ...ClientOption) Client {
+	return &clientV2{
+		client:    NewClient(baseURL, email, apiToken, opts...).(*client),
+		spaceKeys: make(map[string]string),
+	}
+}
+
+// GetPage retrieves a Confluence page by ID along with its space, labels and attachments
+func (c *clientV2) GetPage(pageID string) (*model.ConfluencePage, error) {
+	endpoint := fmt.Sprintf("/wiki/api/v2/pages/%s", url.PathEscape(pageID))
+	params := url.Values{
+		"body-format": []string{string(c.bodyFormat)},
+	}
+
+	var apiPage model.ConfluenceV2Page
+	if err := c.getJSON(endpoint+"?"+params.Encode(), fmt.Sprintf("get page %s", pageID), &apiPage); err != nil {
+		return nil, err
+	}
+
+	spaceKey, err := c.spaceKey(apiPage.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := getAllV2[model.ConfluenceV2Label](c, fmt.Sprintf("/wiki/api/v2/pages/%s/labels", url.PathEscape(pageID)), fmt.Sprintf("get labels for %s", pageID))
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := getAllV2[model.ConfluenceV2Attachment](c, fmt.Sprintf("/wiki/api/v2/pages/%s/attachments", url.PathEscape(pageID)), fmt.Sprintf("get attachments for %s", pageID))
+	if err != nil {
+		return nil, err
+	}
+
+	page := model.ConvertV2PageToModel(&apiPage, spaceKey, labels, attachments)
+
+	// v2 only returns account IDs, so resolve display names for frontmatter
+	page.CreatedBy.DisplayName = c.displayName(page.CreatedBy.AccountID)
+	page.UpdatedBy.DisplayName = c.displayName(page.UpdatedBy.AccountID)
+
+	return page, nil
+}
+
+// GetChildPages retrieves all child pages for a given page ID.
+// The v2 children endpoint returns summaries only, so the returned pages
+// carry no body; use GetPage to fetch their content.
+func (c *clientV2) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
+	endpoint := fmt.Sprintf("/wiki/api/v2/pages/%s/children", url.PathEscape(pageID))
+	children, err := getAllV2[model.ConfluenceV2ChildPage](c, endpoint, fmt.Sprintf("get child pages for %s", pageID))
+	if err != nil {
+		return nil, err
+	}
+
+	childPages := make([]*model.ConfluencePage, 0, len(children))
+	for _, child := range children {
+		spaceKey, err := c.spaceKey(child.SpaceID)
+		if err != nil {
+			return nil, err
+		}
+		childPages = append(childPages, &model.ConfluencePage{
+			ID:       child.ID,
+			Title:    child.Title,
+			SpaceKey: spaceKey,
+		})
+	}
+
+	return childPages, nil
+}
+
+// spaceKey resolves a v2 space ID to its key, caching the result
+func (c *clientV2) spaceKey(spaceID string) (string, error) {
+	if spaceID == "" {
+		return "", nil
+	}
+	if key, ok := c.spaceKeys[spaceID]; ok {
+		return key, nil
+	}
+
+	var space model.ConfluenceV2Space
+	endpoint := fmt.Sprintf("/wiki/api/v2/spaces/%s", url.PathEscape(spaceID))
+	if err := c.getJSON(endpoint, fmt.Sprintf("get space %s", spaceID), &space); err != nil {
+		return "", err
+	}
+
+	c.spaceKeys[spaceID] = space.Key
+	return space.Key, nil
+}
+
+// displayName looks up a user's display name, returning "" when unavailable
+func (c *clientV2) displayName(accountID string) string {
+	if accountID == "" {
+		return ""
+	}
+
+	user, err := c.GetUser(accountID)
+	if err != nil {
+		return ""
+	}
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.PublicName
+}
+
+// getJSON issues a GET request for a v2 endpoint and decodes the JSON response
+func (c *clientV2) getJSON(endpoint, operation string, out any) error {
+	resp, err := c.makeRequest("GET", c.v2URL(endpoint), nil)
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", operation, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.handleErrorResponse(resp, operation)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", operation, err)
+	}
+
+	return nil
+}
+
+// v2URL builds an absolute URL from an endpoint or a cursor link returned by the API.
+// Cursor links are relative to the site and may omit the /wiki context path.
+func (c *clientV2) v2URL(link string) string {
+	if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
+		return link
+	}
+	if !strings.HasPrefix(link, "/") {
+		link = "/" + link
+	}
+	if strings.HasPrefix(link, "/api/v2/") {
+		link = "/wiki" + link
+	}
+	return c.baseURL + link
+}
+
+// getAllV2 follows cursor-based pagination and collects every result of a v2 list endpoint
+func getAllV2[T any](c *clientV2, endpoint, operation string) ([]T, error) {
+	params := url.Values{
+		"limit": []string{strconv.Itoa(defaultV2PageLimit)},
+	}
+	next := endpoint + "?" + params.Encode()
+
+	var results []T
+	for next != "" {
+		var page model.ConfluenceV2MultiEntityResult[T]
+		if err := c.getJSON(next, operation, &page); err != nil {
+			return nil, err
+		}
+
+		results = append(results, page.Results...)
+		next = page.Links.Next
+	}
+
+	return results, nil
+}
@@ -0,0 +1,175 @@
+package confluence
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newV2FixtureServer serves recorded v2 responses from testdata/v2 keyed by request path.
+func newV2FixtureServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			key += "?cursor=" + cursor
+		}
+		if accountID := r.URL.Query().Get("accountId"); accountID != "" {
+			key += "?accountId=" + accountID
+		}
+
+		fixture, ok := routes[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"statusCode":404,"message":"not found: ` + key + `"}`))
+			return
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", "v2", fixture))
+		if err != nil {
+			t.Errorf("failed to read fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientV2GetPage(t *testing.T) {
+	server := newV2FixtureServer(t, map[string]string{
+		"/wiki/api/v2/pages/12345":                    "page.json",
+		"/wiki/api/v2/spaces/98304":                   "space.json",
+		"/wiki/api/v2/pages/12345/labels":             "labels.json",
+		"/wiki/api/v2/pages/12345/attachments":        "attachments.json",
+		"/wiki/rest/api/user?accountId=557058:author": "user_author.json",
+		"/wiki/rest/api/user?accountId=557058:editor": "user_editor.json",
+	})
+
+	page, err := NewClientV2(server.URL, "user@example.com", "token").GetPage("12345")
+	if err != nil {
+		t.Fatalf("GetPage returned error: %v", err)
+	}
+
+	if page.ID != "12345" || page.Title != "Runbook" || page.SpaceKey != "OPS" || page.Version != 7 {
+		t.Fatalf("unexpected page identity: %+v", page)
+	}
+	if page.Content.Storage.Representation != "storage" || !strings.Contains(page.Content.Storage.Value, "<strong>worker</strong>") {
+		t.Fatalf("unexpected body: %+v", page.Content.Storage)
+	}
+	if got := page.GetLabelNames(); len(got) != 2 || got[0] != "runbook" || got[1] != "oncall" {
+		t.Fatalf("unexpected labels: %#v", got)
+	}
+	if len(page.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(page.Attachments))
+	}
+	att := page.Attachments[0]
+	if att.Title != "diagram.png" || att.MediaType != "image/png" || att.FileSize != 2048 || att.Version != 2 {
+		t.Fatalf("unexpected attachment: %+v", att)
+	}
+	if !strings.HasPrefix(att.DownloadLink, "/download/attachments/12345/diagram.png") {
+		t.Fatalf("unexpected download link: %s", att.DownloadLink)
+	}
+	if att.FileID != "5f1c3b8e-0000-4000-8000-000000000001" {
+		t.Fatalf("unexpected file id: %s", att.FileID)
+	}
+	if err := page.Validate(); err != nil {
+		t.Fatalf("mapped page should validate: %v", err)
+	}
+
+	if !page.CreatedAt.Equal(time.Date(2024, 3, 1, 9, 15, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected createdAt: %s", page.CreatedAt)
+	}
+	if !page.UpdatedAt.Equal(time.Date(2024, 4, 2, 10, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected updatedAt: %s", page.UpdatedAt)
+	}
+	if page.CreatedBy.AccountID != "557058:author" || page.CreatedBy.DisplayName != "Ada Author" {
+		t.Fatalf("unexpected createdBy: %+v", page.CreatedBy)
+	}
+	if page.UpdatedBy.AccountID != "557058:editor" || page.UpdatedBy.DisplayName != "Eddie Editor" {
+		t.Fatalf("unexpected updatedBy: %+v", page.UpdatedBy)
+	}
+}
+
+func TestClientV2GetChildPagesFollowsCursor(t *testing.T) {
+	server := newV2FixtureServer(t, map[string]string{
+		"/wiki/api/v2/pages/12345/children":                            "children_page1.json",
+		"/wiki/api/v2/pages/12345/children?cursor=eyJpZCI6IjIwMDAyIn0": "children_page2.json",
+		"/wiki/api/v2/spaces/98304":                                    "space.json",
+	})
+
+	children, err := NewClientV2(server.URL, "user@example.com", "token").GetChildPages("12345")
+	if err != nil {
+		t.Fatalf("GetChildPages returned error: %v", err)
+	}
+
+	wantIDs := []string{"20001", "20002", "20003"}
+	if len(children) != len(wantIDs) {
+		t.Fatalf("expected %d children, got %d", len(wantIDs), len(children))
+	}
+	for i, child := range children {
+		if child.ID != wantIDs[i] {
+			t.Fatalf("child %d: expected id %s, got %s", i, wantIDs[i], child.ID)
+		}
+		if child.SpaceKey != "OPS" {
+			t.Fatalf("child %d: expected space OPS, got %s", i, child.SpaceKey)
+		}
+	}
+	if children[2].Title != "Rollback" {
+		t.Fatalf("unexpected title: %s", children[2].Title)
+	}
+}
+
+func TestClientV2ErrorResponse(t *testing.T) {
+	server := newV2FixtureServer(t, map[string]string{})
+
+	_, err := NewClientV2(server.URL, "user@example.com", "token").GetPage("404")
+	if err == nil || !strings.Contains(err.Error(), "get page 404") {
+		t.Fatalf("expected get page error, got %v", err)
+	}
+}
+
+func TestFallbackClientUsesV1WhenV2Fails(t *testing.T) {
+	var v1Calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/wiki/api/v2/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/wiki/rest/api/content/12345" {
+			v1Calls++
+			_, _ = w.Write([]byte(`{"id":"12345","title":"Runbook","space":{"key":"OPS"},"body":{"storage":{"value":"<p>v1</p>","representation":"storage"}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClientForVersion(server.URL, "user@example.com", "token", APIVersionV2)
+	if err != nil {
+		t.Fatalf("NewClientForVersion returned error: %v", err)
+	}
+
+	page, err := client.GetPage("12345")
+	if err != nil {
+		t.Fatalf("GetPage returned error: %v", err)
+	}
+	if v1Calls != 1 {
+		t.Fatalf("expected v1 fallback to be called once, got %d", v1Calls)
+	}
+	if page.Content.Storage.Value != "<p>v1</p>" {
+		t.Fatalf("unexpected body: %q", page.Content.Storage.Value)
+	}
+}
+
+func TestNewClientForVersionRejectsUnknown(t *testing.T) {
+	if _, err := NewClientForVersion("https://example.atlassian.net", "", "", "v3"); err == nil {
+		t.Fatal("expected error for unsupported API version")
+	}
+}
@@ -0,0 +1,77 @@
+package confluence
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// APIVersion selects which Confluence REST API a client talks to
+type APIVersion string
+
+const (
+	APIVersionV1 APIVersion = "v1"
+	APIVersionV2 APIVersion = "v2"
+)
+
+// NewClientForVersion creates a client for the requested API version.
+// A v2 client falls back to the v1 API for any call that fails, so sites
+// without v2 support keep working.
+func NewClientForVersion(baseURL, email, apiToken string, version APIVersion, opts ...ClientOption) (Client, error) {
+	switch version {
+	case "", APIVersionV1:
+		return NewClient(baseURL, email, apiToken, opts...), nil
+	case APIVersionV2:
+		return NewFallbackClient(NewClientV2(baseURL, email, apiToken, opts...), NewClient(baseURL, email, apiToken, opts...)), nil
+	default:
+		return nil, fmt.Errorf("unsupported API version %q (expected %q or %q)", version, APIVersionV1, APIVersionV2)
+	}
+}
+
+// fallbackClient tries the primary client first and retries failed calls against the fallback
+type fallbackClient struct {
+	primary  Client
+	fallback Client
+}
+
+// NewFallbackClient wraps two clients so that errors from primary are retried on fallback
+func NewFallbackClient(primary, fallback Client) Client {
+	return &fallbackClient{primary: primary, fallback: fallback}
+}
+
+func (c *fallbackClient) GetPage(pageID string) (*model.ConfluencePage, error) {
+	page, err := c.primary.GetPage(pageID)
+	if err == nil {
+		return page, nil
+	}
+	log.Printf("Falling back to v1 API for page %s: %v", pageID, err)
+	return c.fallback.GetPage(pageID)
+}
+
+func (c *fallbackClient) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
+	pages, err := c.primary.GetChildPages(pageID)
+	if err == nil {
+		return pages, nil
+	}
+	log.Printf("Falling back to v1 API for child pages of %s: %v", pageID, err)
+	return c.fallback.GetChildPages(pageID)
+}
+
+// DownloadAttachmentContent, GetUser, FindContent and SearchContent use the same v1 endpoints
+// in both clients, so retrying them on the fallback would only repeat the request.
+func (c *fallbackClient) DownloadAttachmentContent(attachment *model.ConfluenceAttachment) ([]byte, error) {
+	return c.primary.DownloadAttachmentContent(attachment)
+}
+
+func (c *fallbackClient) GetUser(accountID string) (*model.ConfluenceUser, error) {
+	return c.primary.GetUser(accountID)
+}
+
+func (c *fallbackClient) FindContent(ref model.ContentRef) (*model.ConfluencePage, error) {
+	return c.primary.FindContent(ref)
+}
+
+func (c *fallbackClient) SearchContent(cql string, limit int) ([]*model.ConfluencePage, error) {
+	return c.primary.SearchContent(cql, limit)
+}
@@ -11,10 +11,8 @@ type ConfluenceAPIPage struct {
 	Status string `json:"status"`
 	Title  string `json:"title"`
 	Body   struct {
-		Storage struct {
-			Value          string `json:"value"`
-			Representation string `json:"representation"`
-		} `json:"storage"`
+		Storage        ContentStorage `json:"storage"`
+		AtlasDocFormat ContentStorage `json:"atlas_doc_format"`
 	} `json:"body"`
 	Version struct {
 		Number int       `json:"number"`
@@ -59,6 +57,7 @@ type ConfluenceAPIPage struct {
 				Extensions struct {
 					MediaType string `json:"mediaType"`
 					FileSize  int64  `json:"fileSize"`
+					FileID    string `json:"fileId"`
 				} `json:"extensions"`
 				Links struct {
 					Download string `json:"download"`
@@ -66,6 +65,10 @@ type ConfluenceAPIPage struct {
 			} `json:"results"`
 		} `json:"attachment"`
 	} `json:"children"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
 }
 
 // ConfluenceSearchResult represents the API response for search queries
@@ -76,6 +79,17 @@ type ConfluenceSearchResult struct {
 	Size    int                 `json:"size"`
 }
 
+// ConfluenceAPISpace represents a space from the API
+type ConfluenceAPISpace struct {
+	ID       int64  `json:"id"`
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Homepage struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"homepage"`
+}
+
 // ConfluenceErrorResponse represents an error response from the API
 type ConfluenceErrorResponse struct {
 	StatusCode int    `json:"statusCode"`
@@ -111,6 +125,7 @@ func ConvertAPIPageToModel(apiPage *ConfluenceAPIPage) *ConfluencePage {
 			Title:        att.Title,
 			MediaType:    att.Extensions.MediaType,
 			FileSize:     att.Extensions.FileSize,
+			FileID:       att.Extensions.FileID,
 			DownloadLink: att.Links.Download,
 			Version:      att.Version.Number,
 		})
@@ -122,10 +137,7 @@ func ConvertAPIPageToModel(apiPage *ConfluenceAPIPage) *ConfluencePage {
 		SpaceKey: apiPage.Space.Key,
 		Version:  apiPage.Version.Number,
 		Content: ConfluenceContent{
-			Storage: ContentStorage{
-				Value:          apiPage.Body.Storage.Value,
-				Representation: apiPage.Body.Storage.Representation,
-			},
+			Storage: selectBody(apiPage.Body.Storage, apiPage.Body.AtlasDocFormat),
 		},
 		Metadata: ConfluenceMetadata{
 			Labels:     labels,
@@ -146,3 +158,18 @@ func ConvertAPIPageToModel(apiPage *ConfluenceAPIPage) *ConfluencePage {
 		},
 	}
 }
+
+// selectBody picks the populated body representation, preferring storage format.
+// Representation is filled in when the API omits it so callers can tell which body was used.
+func selectBody(storage, adf ContentStorage) ContentStorage {
+	if storage.Value == "" && adf.Value != "" {
+		if adf.Representation == "" {
+			adf.Representation = RepresentationADF
+		}
+		return adf
+	}
+	if storage.Representation == "" {
+		storage.Representation = RepresentationStorage
+	}
+	return storage
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"time"
+)
+
+// ConfluenceV2Page represents a page returned by the v2 /wiki/api/v2/pages endpoints
+type ConfluenceV2Page struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Title     string    `json:"title"`
+	SpaceID   string    `json:"spaceId"`
+	ParentID  string    `json:"parentId"`
+	AuthorID  string    `json:"authorId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Version   struct {
+		Number    int       `json:"number"`
+		CreatedAt time.Time `json:"createdAt"`
+		AuthorID  string    `json:"authorId"`
+	} `json:"version"`
+	Body struct {
+		Storage        ContentStorage `json:"storage"`
+		AtlasDocFormat ContentStorage `json:"atlas_doc_format"`
+	} `json:"body"`
+	Links struct {
+		WebUI string `json:"webui"`
+		Base  string `json:"base"`
+	} `json:"_links"`
+}
+
+// ConfluenceV2ChildPage represents an entry returned by the v2 /children endpoint
+type ConfluenceV2ChildPage struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	Title         string `json:"title"`
+	SpaceID       string `json:"spaceId"`
+	ChildPosition int    `json:"childPosition"`
+}
+
+// ConfluenceV2Space represents a space returned by the v2 /spaces endpoint
+type ConfluenceV2Space struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// ConfluenceV2Label represents a label returned by the v2 /labels endpoint
+type ConfluenceV2Label struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+}
+
+// ConfluenceV2Attachment represents an attachment returned by the v2 /attachments endpoint
+type ConfluenceV2Attachment struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Title        string `json:"title"`
+	MediaType    string `json:"mediaType"`
+	FileID       string `json:"fileId"`
+	FileSize     int64  `json:"fileSize"`
+	DownloadLink string `json:"downloadLink"`
+	Version      struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		Download string `json:"download"`
+	} `json:"_links"`
+}
+
+// ConfluenceV2MultiEntityResult represents a cursor-paginated v2 list response
+type ConfluenceV2MultiEntityResult[T any] struct {
+	Results []T `json:"results"`
+	Links   struct {
+		Next string `json:"next"`
+		Base string `json:"base"`
+	} `json:"_links"`
+}
+
+// ConvertV2PageToModel converts v2 API responses to our domain model
+func ConvertV2PageToModel(apiPage *ConfluenceV2Page, spaceKey string, apiLabels []ConfluenceV2Label, apiAttachments []ConfluenceV2Attachment) *ConfluencePage {
+	var labels []Label
+	for _, apiLabel := range apiLabels {
+		labels = append(labels, Label{
+			ID:   apiLabel.ID,
+			Name: apiLabel.Name,
+		})
+	}
+
+	var attachments []ConfluenceAttachment
+	for _, att := range apiAttachments {
+		downloadLink := att.DownloadLink
+		if downloadLink == "" {
+			downloadLink = att.Links.Download
+		}
+		attachments = append(attachments, ConfluenceAttachment{
+			ID:           att.ID,
+			Title:        att.Title,
+			MediaType:    att.MediaType,
+			FileSize:     att.FileSize,
+			DownloadLink: downloadLink,
+			Version:      att.Version.Number,
+			FileID:       att.FileID,
+		})
+	}
+
+	return &ConfluencePage{
+		ID:       apiPage.ID,
+		Title:    apiPage.Title,
+		SpaceKey: spaceKey,
+		Version:  apiPage.Version.Number,
+		Content: ConfluenceContent{
+			Storage: selectBody(apiPage.Body.Storage, apiPage.Body.AtlasDocFormat),
+		},
+		Metadata: ConfluenceMetadata{
+			Labels:     labels,
+			Properties: make(map[string]string),
+		},
+		Attachments: attachments,
+		CreatedAt:   apiPage.CreatedAt,
+		UpdatedAt:   apiPage.Version.CreatedAt,
+		CreatedBy: User{
+			AccountID: apiPage.AuthorID,
+		},
+		UpdatedBy: User{
+			AccountID: apiPage.Version.AuthorID,
+		},
+	}
+}
@@ -26,10 +26,17 @@ type ConfluenceContent struct {
 	Storage ContentStorage `json:"storage"`
 }
 
-// ContentStorage represents the storage format of Confluence content
+// Body representations understood by the converter
+const (
+	RepresentationStorage = "storage"          // Storage format XHTML
+	RepresentationADF     = "atlas_doc_format" // Atlassian Document Format JSON
+	RepresentationView    = "view"             // Rendered view HTML, as found in HTML space exports
+)
+
+// ContentStorage represents the body of Confluence content
 type ContentStorage struct {
-	Value          string `json:"value"`          // HTML content
-	Representation string `json:"representation"` // Always "storage"
+	Value          string `json:"value"`          // HTML content, or ADF JSON
+	Representation string `json:"representation"` // Which body was used, e.g. "storage" or "atlas_doc_format"
 }
 
 // ConfluenceMetadata contains page metadata from Confluence
@@ -52,6 +59,7 @@ type ConfluenceAttachment struct {
 	FileSize     int64  `json:"fileSize"`
 	DownloadLink string `json:"downloadLink"`
 	Version      int    `json:"version"`
+	FileID       string `json:"fileId,omitempty"` // Media file ID, used by ADF media nodes
 }
 
 // User represents a Confluence user
@@ -141,6 +149,21 @@ func (ca *ConfluenceAttachment) Validate() error {
 	return nil
 }
 
+// Content types that storage-format links can reference
+const (
+	ContentTypePage     = "page"
+	ContentTypeBlogPost = "blogpost"
+	ContentTypeSpace    = "space" // Resolves to the space home page
+)
+
+// ContentRef identifies linked content by space and title, as storage-format links do
+type ContentRef struct {
+	Type       string
+	SpaceKey   string
+	Title      string
+	PostingDay string // YYYY/MM/DD, blog posts only
+}
+
 // PageURLInfo contains information extracted from a Confluence page URL
 type PageURLInfo struct {
 	BaseURL  string
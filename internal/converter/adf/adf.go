@@ -0,0 +1,104 @@
+// Package adf converts Atlassian Document Format (ADF) bodies to Markdown.
+package adf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a node in an ADF document tree
+type Node struct {
+	Type    string         `json:"type"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Content []*Node        `json:"content,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Marks   []Mark         `json:"marks,omitempty"`
+}
+
+// Mark is a formatting mark applied to an ADF text node
+type Mark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// Parse decodes an ADF JSON document
+func Parse(data string) (*Node, error) {
+	var doc Node
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse ADF document: %w", err)
+	}
+	if doc.Type != "doc" {
+		return nil, fmt.Errorf("unexpected ADF root node %q", doc.Type)
+	}
+	return &doc, nil
+}
+
+// IsDocument reports whether the input looks like an ADF JSON document
+func IsDocument(data string) bool {
+	trimmed := strings.TrimSpace(data)
+	if !strings.HasPrefix(trimmed, "{") {
+		return false
+	}
+	_, err := Parse(trimmed)
+	return err == nil
+}
+
+// attr returns a string attribute, formatting numbers without a trailing fraction
+func (n *Node) attr(name string) string {
+	if n == nil || n.Attrs == nil {
+		return ""
+	}
+	return stringValue(n.Attrs[name])
+}
+
+// attr returns a string attribute of the mark
+func (m Mark) attr(name string) string {
+	if m.Attrs == nil {
+		return ""
+	}
+	return stringValue(m.Attrs[name])
+}
+
+func stringValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// macroParam returns a parameter of an extension node's macro, stored under
+// attrs.parameters.macroParams.<name>.value
+func (n *Node) macroParam(name string) string {
+	if n == nil || n.Attrs == nil {
+		return ""
+	}
+	parameters, _ := n.Attrs["parameters"].(map[string]any)
+	params, _ := parameters["macroParams"].(map[string]any)
+	param, _ := params[name].(map[string]any)
+	return stringValue(param["value"])
+}
+
+// textContent concatenates all text beneath the node
+func (n *Node) textContent() string {
+	if n == nil {
+		return ""
+	}
+	if n.Type == "text" {
+		return n.Text
+	}
+	var b strings.Builder
+	for _, child := range n.Content {
+		b.WriteString(child.textContent())
+	}
+	return b.String()
+}
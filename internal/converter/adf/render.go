@@ -0,0 +1,615 @@
+package adf
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/callout"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
+	mdmodel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+var backtickRun = regexp.MustCompile("`{3,}")
+
+// statusEmoji mirrors the colours used by the storage-format status macro
+var statusEmoji = map[string]string{
+	"red":     "🔴",
+	"yellow":  "🟡",
+	"green":   "🟢",
+	"blue":    "🔵",
+	"neutral": "⚪",
+	"grey":    "⚪",
+	"gray":    "⚪",
+}
+
+// Renderer converts ADF nodes to Markdown
+type Renderer struct {
+	imageFolder string
+	attachments []model.ConfluenceAttachment
+	images      []string
+	callouts    *callout.Renderer
+	layouts     layout.Style
+	excerpt     string
+	tasks       []mdmodel.Task
+}
+
+// NewRenderer creates a renderer that resolves media nodes against the page attachments
+func NewRenderer(imageFolder string, attachments []model.ConfluenceAttachment) *Renderer {
+	return &Renderer{
+		imageFolder: imageFolder,
+		attachments: attachments,
+		callouts:    callout.New(callout.StyleBlockquote, nil),
+	}
+}
+
+// SetCallouts selects the renderer for panels and expands
+func (r *Renderer) SetCallouts(callouts *callout.Renderer) {
+	r.callouts = callouts
+}
+
+// SetLayoutStyle selects how the columns of layout sections are written
+func (r *Renderer) SetLayoutStyle(style layout.Style) {
+	r.layouts = style
+}
+
+// Render converts an ADF document to Markdown
+func (r *Renderer) Render(doc *Node) string {
+	return r.renderBlocks(doc.Content)
+}
+
+// Excerpt returns the plain text of the document's excerpt macro
+func (r *Renderer) Excerpt() string {
+	return r.excerpt
+}
+
+// Tasks returns the document's tasks in document order, nested tasks included
+func (r *Renderer) Tasks() []mdmodel.Task {
+	return r.tasks
+}
+
+// Images returns the attachment filenames referenced by media nodes
+func (r *Renderer) Images() []string {
+	return r.images
+}
+
+// renderBlocks renders block nodes separated by blank lines
+func (r *Renderer) renderBlocks(nodes []*Node) string {
+	var parts []string
+	for _, n := range nodes {
+		if out := strings.TrimRight(r.renderBlock(n), "\n"); strings.TrimSpace(out) != "" {
+			parts = append(parts, out)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (r *Renderer) renderBlock(n *Node) string {
+	switch n.Type {
+	case "paragraph":
+		return r.renderInline(n.Content)
+	case "heading":
+		level, _ := strconv.Atoi(n.attr("level"))
+		if level < 1 || level > 6 {
+			level = 1
+		}
+		return strings.Repeat("#", level) + " " + r.renderInline(n.Content)
+	case "bulletList":
+		return r.renderList(n, false)
+	case "orderedList":
+		return r.renderList(n, true)
+	case "taskList":
+		return r.renderTaskList(n)
+	case "decisionList":
+		return r.renderDecisionList(n)
+	case "codeBlock":
+		code := n.textContent()
+		fence := codeFence(code)
+		return fence + n.attr("language") + "\n" + code + "\n" + fence
+	case "blockquote":
+		return quote(r.renderBlocks(n.Content))
+	case "rule":
+		return "* * *"
+	case "panel":
+		return r.renderPanel(n)
+	case "table":
+		return r.renderTable(n)
+	case "mediaSingle", "mediaGroup":
+		var images []string
+		for _, child := range n.Content {
+			if image := r.renderMedia(child); image != "" {
+				images = append(images, image)
+			}
+		}
+		return strings.Join(images, "\n\n")
+	case "media":
+		return r.renderMedia(n)
+	case "blockCard", "embedCard":
+		return r.renderCard(n)
+	case "expand", "nestedExpand":
+		return r.renderExpand(n)
+	case "layoutSection":
+		return r.renderLayoutSection(n)
+	case "bodiedExtension":
+		if n.attr("extensionKey") == "excerpt" {
+			return r.renderExcerpt(n)
+		}
+		return r.renderBlocks(n.Content)
+	case "layoutColumn":
+		// Containers are linearized so their content is kept in reading order
+		return r.renderBlocks(n.Content)
+	case "extension":
+		return fmt.Sprintf("<!-- Unsupported macro: %s -->", n.attr("extensionKey"))
+	default:
+		if len(n.Content) > 0 && isInline(n.Content[0]) {
+			return r.renderInline(n.Content)
+		}
+		if len(n.Content) > 0 {
+			return r.renderBlocks(n.Content)
+		}
+		return r.renderInline([]*Node{n})
+	}
+}
+
+// renderExcerpt records the first excerpt as the page description and shows
+// its content unless the macro is hidden
+func (r *Renderer) renderExcerpt(n *Node) string {
+	if r.excerpt == "" {
+		var words []string
+		for _, child := range n.Content {
+			words = append(words, strings.Fields(child.textContent())...)
+		}
+		r.excerpt = strings.Join(words, " ")
+	}
+	if n.macroParam("hidden") == "true" {
+		return ""
+	}
+	return r.renderBlocks(n.Content)
+}
+
+// codeFence returns a backtick fence longer than any backtick run in the code
+func codeFence(code string) string {
+	fence := "```"
+	for _, run := range backtickRun.FindAllString(code, -1) {
+		if len(run) >= len(fence) {
+			fence = strings.Repeat("`", len(run)+1)
+		}
+	}
+	return fence
+}
+
+func isInline(n *Node) bool {
+	switch n.Type {
+	case "text", "hardBreak", "mention", "emoji", "date", "status", "inlineCard", "placeholder", "inlineExtension", "mediaInline":
+		return true
+	}
+	return false
+}
+
+// renderInline renders inline nodes into a single Markdown string
+func (r *Renderer) renderInline(nodes []*Node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(applyMarks(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("  \n")
+		case "mention":
+			name := n.attr("text")
+			if name == "" {
+				name = fmt.Sprintf("@user(%s)", n.attr("id"))
+			} else if !strings.HasPrefix(name, "@") {
+				name = "@" + name
+			}
+			fmt.Fprintf(&b, " %s ", name)
+		case "emoji":
+			if text := n.attr("text"); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(n.attr("shortName"))
+			}
+		case "date":
+			b.WriteString(formatTimestamp(n.attr("timestamp")))
+		case "status":
+			b.WriteString(renderStatus(n.attr("text"), n.attr("color")))
+		case "inlineCard":
+			b.WriteString(r.renderCard(n))
+		case "placeholder":
+			if text := strings.TrimSpace(n.attr("text")); text != "" {
+				fmt.Fprintf(&b, "<!-- %s -->", text)
+			}
+		case "inlineExtension":
+			fmt.Fprintf(&b, "<!-- Unsupported macro: %s -->", n.attr("extensionKey"))
+		case "mediaInline":
+			b.WriteString(r.renderMedia(n))
+		default:
+			b.WriteString(r.renderInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+// applyMarks wraps text in the Markdown syntax for its marks
+func applyMarks(text string, marks []Mark) string {
+	if text == "" {
+		return ""
+	}
+
+	var code, strong, em, strike bool
+	href := ""
+	for _, mark := range marks {
+		switch mark.Type {
+		case "code":
+			code = true
+		case "strong":
+			strong = true
+		case "em":
+			em = true
+		case "strike":
+			strike = true
+		case "link":
+			href = mark.attr("href")
+		}
+	}
+
+	// Keep surrounding whitespace outside of the delimiters so they still parse
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+
+	out := escapeText(trimmed)
+	if code {
+		out = "`" + trimmed + "`"
+	}
+	if strike {
+		out = "~~" + out + "~~"
+	}
+	if em {
+		out = "*" + out + "*"
+	}
+	if strong {
+		out = "**" + out + "**"
+	}
+	if href != "" {
+		out = fmt.Sprintf("[%s](%s)", out, href)
+	}
+
+	return leading + out + trailing
+}
+
+// escapeText escapes characters that would otherwise be read as Markdown syntax
+func escapeText(text string) string {
+	var b strings.Builder
+	for i, ch := range text {
+		switch ch {
+		case '\\', '*', '`', '[', ']':
+			b.WriteRune('\\')
+		case '_':
+			// Intra-word underscores (snake_case) are not emphasis
+			if i > 0 && i < len(text)-1 && isWordByte(text[i-1]) && isWordByte(text[i+1]) {
+				break
+			}
+			b.WriteRune('\\')
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// renderList renders bullet and ordered lists, indenting nested content under each marker
+func (r *Renderer) renderList(n *Node, ordered bool) string {
+	start := 1
+	if order, err := strconv.Atoi(n.attr("order")); err == nil && order > 0 {
+		start = order
+	}
+
+	var items []string
+	for i, item := range n.Content {
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", start+i)
+		}
+		items = append(items, listItem(marker, r.renderListItem(item)))
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderListItem renders the blocks of a list item, keeping nested lists tight
+func (r *Renderer) renderListItem(item *Node) string {
+	var b strings.Builder
+	for i, child := range item.Content {
+		out := strings.TrimRight(r.renderBlock(child), "\n")
+		if out == "" {
+			continue
+		}
+		if i > 0 && b.Len() > 0 {
+			if isList(child) {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(out)
+	}
+	return b.String()
+}
+
+func isList(n *Node) bool {
+	switch n.Type {
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		return true
+	}
+	return false
+}
+
+// listItem prefixes content with a list marker and indents continuation lines
+func listItem(marker, content string) string {
+	indent := strings.Repeat(" ", len(marker))
+	lines := strings.Split(content, "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = marker + lines[i]
+		} else if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderLayoutSection renders the columns of a layout, whose widths are percentages
+func (r *Renderer) renderLayoutSection(n *Node) string {
+	var columns []layout.Column
+	for _, child := range n.Content {
+		if child.Type != "layoutColumn" {
+			continue
+		}
+		column := layout.Column{Content: r.renderBlocks(child.Content)}
+		if width := child.attr("width"); width != "" {
+			column.Width = width + "%"
+		}
+		columns = append(columns, column)
+	}
+	return layout.Render(r.layouts, columns)
+}
+
+// renderTaskList renders ADF tasks as GitHub task list items
+func (r *Renderer) renderTaskList(n *Node) string {
+	var items []string
+	for _, item := range n.Content {
+		if item.Type == "taskList" {
+			items = append(items, indentLines(r.renderTaskList(item), "  "))
+			continue
+		}
+		box := "[ ]"
+		if item.attr("state") == "DONE" {
+			box = "[x]"
+		}
+		if task := summarizeTask(item); task.Text != "" {
+			r.tasks = append(r.tasks, task)
+		}
+		items = append(items, listItem("- ", box+" "+r.renderInline(item.Content)))
+	}
+	return strings.Join(items, "\n")
+}
+
+// summarizeTask reads a task item's plain text. The first mentioned user is its
+// assignee and the first date its due date, as for storage-format tasks.
+func summarizeTask(item *Node) mdmodel.Task {
+	summary := mdmodel.Task{Status: "incomplete"}
+	if item.attr("state") == "DONE" {
+		summary.Status = "complete"
+	}
+
+	var text strings.Builder
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		switch n.Type {
+		case "text":
+			text.WriteString(n.Text)
+		case "mention":
+			name := strings.TrimPrefix(n.attr("text"), "@")
+			if name == "" {
+				name = fmt.Sprintf("user(%s)", n.attr("id"))
+			}
+			if summary.Assignee == "" {
+				summary.Assignee = name
+			}
+			text.WriteString(" @" + name + " ")
+		case "date":
+			due := formatTimestamp(n.attr("timestamp"))
+			if summary.Due == "" {
+				summary.Due = due
+			}
+			text.WriteString(" " + due + " ")
+		case "status", "emoji":
+			text.WriteString(n.attr("text"))
+		case "hardBreak", "paragraph":
+			text.WriteString(" ")
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+	}
+	for _, child := range item.Content {
+		walk(child)
+	}
+	summary.Text = strings.Join(strings.Fields(text.String()), " ")
+	return summary
+}
+
+// renderDecisionList renders decisions as checklist items marked with ✔
+func (r *Renderer) renderDecisionList(n *Node) string {
+	var items []string
+	for _, item := range n.Content {
+		text := r.renderInline(item.Content)
+		if item.attr("state") == "DECIDED" {
+			items = append(items, listItem("- ", "[x] ✔ "+text))
+		} else {
+			items = append(items, listItem("- ", "[ ] "+text))
+		}
+	}
+	return strings.Join(items, "\n")
+}
+
+func indentLines(content, indent string) string {
+	lines := strings.Split(content, "\n")
+	for i := range lines {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderPanel renders panels in the configured callout style, matching the storage-format callout macros
+func (r *Renderer) renderPanel(n *Node) string {
+	c := callout.Callout{
+		Kind:    callout.PanelKind(n.attr("panelType")),
+		Content: r.renderBlocks(n.Content),
+	}
+	if c.Kind == callout.KindPanel {
+		c.Icon = n.attr("panelIconText")
+		if c.Icon == "" {
+			c.Icon = n.attr("panelIcon")
+		}
+	}
+	return r.callouts.Render(c)
+}
+
+// renderExpand renders expands in the configured callout style, which keeps only the content by default
+func (r *Renderer) renderExpand(n *Node) string {
+	content := r.renderBlocks(n.Content)
+	if content == "" {
+		return ""
+	}
+	return r.callouts.Render(callout.Callout{Kind: callout.KindExpand, Title: n.attr("title"), Content: content})
+}
+
+// quote prefixes every line of content with a blockquote marker
+func quote(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTable renders ADF tables as Markdown tables, flattening multi-line cells
+func (r *Renderer) renderTable(n *Node) string {
+	var rows [][]string
+	maxCols := 0
+	for _, row := range n.Content {
+		if row.Type != "tableRow" {
+			continue
+		}
+		var cells []string
+		for _, cell := range row.Content {
+			content := r.renderBlocks(cell.Content)
+			content = strings.ReplaceAll(content, "|", "\\|")
+			content = strings.ReplaceAll(content, "\n\n", "<br>")
+			content = strings.ReplaceAll(content, "  \n", "<br>")
+			content = strings.ReplaceAll(content, "\n", "<br>")
+			if strings.TrimSpace(content) == "" {
+				content = " "
+			}
+			cells = append(cells, content)
+		}
+		if len(cells) > maxCols {
+			maxCols = len(cells)
+		}
+		rows = append(rows, cells)
+	}
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < maxCols {
+			row = append(row, " ")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat("---|", maxCols) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// renderMedia renders media nodes as image references into the image folder
+func (r *Renderer) renderMedia(n *Node) string {
+	if n.attr("type") == "external" {
+		return fmt.Sprintf("![%s](%s)", n.attr("alt"), n.attr("url"))
+	}
+
+	filename := r.mediaFilename(n)
+	if filename == "" {
+		return "<!-- Image attachment not found -->"
+	}
+
+	r.images = append(r.images, filename)
+	localPath := r.imageFolder + "/" + filename
+	return fmt.Sprintf("![%s](%s)", filename, url.PathEscape(localPath))
+}
+
+// mediaFilename maps a media node to a page attachment title via its file ID or alt text
+func (r *Renderer) mediaFilename(n *Node) string {
+	id := n.attr("id")
+	alt := n.attr("alt")
+	for _, attachment := range r.attachments {
+		if id != "" && attachment.FileID == id {
+			return attachment.Title
+		}
+	}
+	for _, attachment := range r.attachments {
+		if alt != "" && strings.EqualFold(attachment.Title, alt) {
+			return attachment.Title
+		}
+	}
+	return alt
+}
+
+func (r *Renderer) renderCard(n *Node) string {
+	link := n.attr("url")
+	if link == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s](%s)", link, link)
+}
+
+// renderStatus mirrors the storage-format status macro output
+func renderStatus(text, color string) string {
+	if text == "" {
+		return ""
+	}
+	if emoji, ok := statusEmoji[strings.ToLower(color)]; ok {
+		return fmt.Sprintf("%s **%s**", emoji, text)
+	}
+	return fmt.Sprintf("**[%s]**", text)
+}
+
+// formatTimestamp converts an ADF millisecond timestamp to a date
+func formatTimestamp(value string) string {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return value
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
@@ -0,0 +1,176 @@
+package adf
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
+	mdmodel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+func render(t *testing.T, body string) (string, *Renderer) {
+	t.Helper()
+	doc, err := Parse(`{"type":"doc","version":1,"content":[` + body + `]}`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	r := NewRenderer("assets", []model.ConfluenceAttachment{{Title: "diagram.png", FileID: "file-1"}})
+	return r.Render(doc), r
+}
+
+func TestRenderBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "heading and marks",
+			body: `{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Title"}]},
+				{"type":"paragraph","content":[{"type":"text","text":"bold ","marks":[{"type":"strong"}]},{"type":"text","text":"code","marks":[{"type":"code"}]},{"type":"text","text":" and "},{"type":"text","text":"link","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}]}`,
+			want: "## Title\n\n**bold** `code` and [link](https://example.com)",
+		},
+		{
+			name: "nested lists",
+			body: `{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]},{"type":"orderedList","attrs":{"order":3},"content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"three"}]}]}]}]}]}`,
+			want: "- one\n  3. three",
+		},
+		{
+			name: "code block",
+			body: `{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"fmt.Println(1)"}]}`,
+			want: "```go\nfmt.Println(1)\n```",
+		},
+		{
+			name: "code block containing a fence",
+			body: "{\"type\":\"codeBlock\",\"attrs\":{\"language\":\"markdown\"},\"content\":[{\"type\":\"text\",\"text\":\"```go\\nx\\n```\"}]}",
+			want: "````markdown\n```go\nx\n```\n````",
+		},
+		{
+			name: "info panel",
+			body: `{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Heads up"}]}]}`,
+			want: "> ℹ️ **Info:** Heads up",
+		},
+		{
+			name: "custom emoji panel",
+			body: `{"type":"panel","attrs":{"panelType":"custom","panelIconText":"🚀"},"content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]},{"type":"paragraph","content":[{"type":"text","text":"two"}]}]}`,
+			want: "> 🚀\n> one\n>\n> two",
+		},
+		{
+			name: "task and decision lists",
+			body: `{"type":"taskList","content":[{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"ship"}]},{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"test"}]}]},
+				{"type":"decisionList","content":[{"type":"decisionItem","attrs":{"state":"DECIDED"},"content":[{"type":"text","text":"use ADF"}]}]}`,
+			want: "- [x] ship\n- [ ] test\n\n- [x] ✔ use ADF",
+		},
+		{
+			name: "table",
+			body: `{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"A"}]}]},{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"B"}]}]}]},{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1"}]},{"type":"paragraph","content":[{"type":"text","text":"2"}]}]},{"type":"tableCell","content":[]}]}]}`,
+			want: "| A | B |\n|---|---|\n| 1<br>2 |   |",
+		},
+		{
+			name: "inline nodes",
+			body: `{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"abc","text":"@Ada"}},{"type":"text","text":"on "},{"type":"date","attrs":{"timestamp":"1704153600000"}},{"type":"text","text":" "},{"type":"status","attrs":{"text":"DONE","color":"green"}},{"type":"emoji","attrs":{"shortName":":smile:","text":"😄"}}]}`,
+			want: " @Ada on 2024-01-02 🟢 **DONE**😄",
+		},
+		{
+			name: "layout linearized",
+			body: `{"type":"layoutSection","content":[{"type":"layoutColumn","content":[{"type":"paragraph","content":[{"type":"text","text":"left"}]}]},{"type":"layoutColumn","content":[{"type":"paragraph","content":[{"type":"text","text":"right"}]}]}]}`,
+			want: "left\n\nright",
+		},
+		{
+			name: "unsupported extension",
+			body: `{"type":"extension","attrs":{"extensionKey":"jira"}}`,
+			want: "<!-- Unsupported macro: jira -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := render(t, tt.body)
+			if got != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMedia(t *testing.T) {
+	got, r := render(t, `{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"file-1","collection":"contentId-1"}}]}`)
+	if got != "![diagram.png](assets%2Fdiagram.png)" {
+		t.Fatalf("unexpected markdown: %q", got)
+	}
+	if images := r.Images(); len(images) != 1 || images[0] != "diagram.png" {
+		t.Fatalf("unexpected images: %#v", images)
+	}
+}
+
+func TestRenderExcerpt(t *testing.T) {
+	excerpt := func(hidden bool) string {
+		return `{"type":"bodiedExtension","attrs":{"extensionKey":"excerpt","parameters":{"macroParams":{"hidden":{"value":"` + strconv.FormatBool(hidden) + `"}}}},"content":[` +
+			`{"type":"paragraph","content":[{"type":"text","text":"Release  process"}]},{"type":"paragraph","content":[{"type":"text","text":"for ops"}]}]}`
+	}
+
+	got, r := render(t, excerpt(false))
+	if got != "Release  process\n\nfor ops" {
+		t.Fatalf("unexpected markdown: %q", got)
+	}
+	if r.Excerpt() != "Release process for ops" {
+		t.Fatalf("unexpected excerpt: %q", r.Excerpt())
+	}
+
+	got, r = render(t, excerpt(true)+`,{"type":"paragraph","content":[{"type":"text","text":"Body"}]}`)
+	if got != "Body" || r.Excerpt() != "Release process for ops" {
+		t.Fatalf("unexpected hidden excerpt: %q, %q", got, r.Excerpt())
+	}
+}
+
+func TestRenderTasks(t *testing.T) {
+	_, r := render(t, `{"type":"taskList","content":[`+
+		`{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"Ship it "},{"type":"mention","attrs":{"id":"abc","text":"@Jane Doe"}},{"type":"text","text":" by "},{"type":"date","attrs":{"timestamp":"1706659200000"}}]},`+
+		`{"type":"taskList","content":[{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"Write notes"}]}]},`+
+		`{"type":"taskItem","attrs":{"state":"TODO"}}]}`)
+
+	want := []mdmodel.Task{
+		{Status: "incomplete", Text: "Ship it @Jane Doe by 2024-01-31", Assignee: "Jane Doe", Due: "2024-01-31"},
+		{Status: "complete", Text: "Write notes"},
+	}
+	if got := r.Tasks(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tasks:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	if got := escapeText("snake_case *star* [x]"); got != `snake_case \*star\* \[x\]` {
+		t.Fatalf("unexpected escape: %q", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse(`{"type":"paragraph"}`); err == nil || !strings.Contains(err.Error(), "unexpected ADF root") {
+		t.Fatalf("expected root error, got %v", err)
+	}
+	if !IsDocument(` {"type":"doc","content":[]}`) {
+		t.Fatal("expected ADF document detection")
+	}
+	if IsDocument(`<p>html</p>`) {
+		t.Fatal("expected HTML not to be detected as ADF")
+	}
+}
+
+func TestRenderLayoutGrid(t *testing.T) {
+	doc, err := Parse(`{"type":"doc","version":1,"content":[{"type":"layoutSection","content":[` +
+		`{"type":"layoutColumn","attrs":{"width":33.33},"content":[{"type":"paragraph","content":[{"type":"text","text":"left"}]}]},` +
+		`{"type":"layoutColumn","attrs":{"width":66.66},"content":[{"type":"paragraph","content":[{"type":"text","text":"right"}]}]}]}]}`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	r := NewRenderer("assets", nil)
+	r.SetLayoutStyle(layout.StyleGrid)
+
+	want := "<div style=\"display:flex\">\n<div style=\"flex:0 0 33.33%\">\n\nleft\n\n</div>\n<div style=\"flex:0 0 66.66%\">\n\nright\n\n</div>\n</div>"
+	if got := r.Render(doc); got != want {
+		t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, want)
+	}
+}
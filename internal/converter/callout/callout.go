@@ -0,0 +1,306 @@
+// Package callout renders Confluence callouts (info panels, titled panels and
+// expands) in the admonition syntax of a Markdown publishing target
+package callout
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Kind identifies the Confluence container being rendered
+type Kind string
+
+const (
+	KindInfo    Kind = "info"
+	KindNote    Kind = "note"
+	KindWarning Kind = "warning"
+	KindTip     Kind = "tip"
+	KindSuccess Kind = "success"
+	KindError   Kind = "error"
+	KindPanel   Kind = "panel"  // Untyped panel, optionally with a custom emoji
+	KindExpand  Kind = "expand" // Expand macro or ADF expand
+)
+
+// Style selects the Markdown syntax callouts are written in
+type Style string
+
+const (
+	StyleBlockquote Style = "blockquote" // > ℹ️ **Info:** text
+	StyleGFM        Style = "gfm"        // > [!NOTE]
+	StyleMkDocs     Style = "mkdocs"     // !!! note "Title"
+	StyleDocusaurus Style = "docusaurus" // :::note[Title]
+	StyleObsidian   Style = "obsidian"   // > [!note] Title
+)
+
+// Callout is a container to render
+type Callout struct {
+	Kind    Kind
+	Title   string // Explicit title, such as a panel or expand title
+	Icon    string // Custom emoji of Cloud panels
+	Content string // Converted Markdown body
+}
+
+// emoji prefixes the label of typed callouts in the blockquote style
+var emoji = map[Kind]string{
+	KindInfo:    "ℹ️",
+	KindNote:    "📝",
+	KindWarning: "⚠️",
+	KindTip:     "💡",
+	KindSuccess: "✅",
+	KindError:   "❌",
+}
+
+// DefaultLabels are the English labels shown for each kind
+var DefaultLabels = map[Kind]string{
+	KindInfo:    "Info",
+	KindNote:    "Note",
+	KindWarning: "Warning",
+	KindTip:     "Tip",
+	KindSuccess: "Success",
+	KindError:   "Error",
+	KindExpand:  "Click here to expand...",
+}
+
+// admonitionTypes maps kinds to the type keyword of each style
+var admonitionTypes = map[Style]map[Kind]string{
+	StyleGFM: {
+		KindInfo: "NOTE", KindNote: "IMPORTANT", KindWarning: "WARNING",
+		KindTip: "TIP", KindSuccess: "TIP", KindError: "CAUTION",
+	},
+	StyleMkDocs: {
+		KindInfo: "info", KindNote: "note", KindWarning: "warning", KindTip: "tip",
+		KindSuccess: "success", KindError: "danger", KindPanel: "note", KindExpand: "note",
+	},
+	StyleDocusaurus: {
+		KindInfo: "info", KindNote: "note", KindWarning: "warning", KindTip: "tip",
+		KindSuccess: "tip", KindError: "danger", KindPanel: "note", KindExpand: "note",
+	},
+	StyleObsidian: {
+		KindInfo: "info", KindNote: "note", KindWarning: "warning", KindTip: "tip",
+		KindSuccess: "success", KindError: "danger", KindPanel: "note", KindExpand: "note",
+	},
+}
+
+// ParseStyle validates a callout style name
+func ParseStyle(s string) (Style, error) {
+	switch style := Style(strings.ToLower(strings.TrimSpace(s))); style {
+	case StyleBlockquote, StyleGFM, StyleMkDocs, StyleDocusaurus, StyleObsidian:
+		return style, nil
+	case "":
+		return StyleBlockquote, nil
+	default:
+		return "", fmt.Errorf("unsupported callout style: %s (use blockquote, gfm, mkdocs, docusaurus or obsidian)", s)
+	}
+}
+
+// ParseLabels validates label overrides keyed by kind name
+func ParseLabels(labels map[string]string) (map[Kind]string, error) {
+	parsed := make(map[Kind]string, len(labels))
+	for name, label := range labels {
+		kind := Kind(strings.ToLower(strings.TrimSpace(name)))
+		switch kind {
+		case KindInfo, KindNote, KindWarning, KindTip, KindSuccess, KindError, KindPanel, KindExpand:
+			parsed[kind] = label
+		default:
+			return nil, fmt.Errorf("unknown callout kind: %s", name)
+		}
+	}
+	return parsed, nil
+}
+
+// PanelKind maps a Cloud panel type to its kind; custom panels are KindPanel
+func PanelKind(panelType string) Kind {
+	kind := Kind(panelType)
+	if _, ok := emoji[kind]; ok {
+		return kind
+	}
+	return KindPanel
+}
+
+// Renderer writes callouts in one style
+type Renderer struct {
+	style   Style
+	labels  map[Kind]string
+	details bool
+}
+
+// New creates a renderer; labels override DefaultLabels per kind
+func New(style Style, labels map[Kind]string) *Renderer {
+	if style == "" {
+		style = StyleBlockquote
+	}
+	merged := make(map[Kind]string, len(DefaultLabels))
+	for kind, label := range DefaultLabels {
+		merged[kind] = label
+	}
+	for kind, label := range labels {
+		merged[kind] = label
+	}
+	return &Renderer{style: style, labels: merged}
+}
+
+// SetExpandDetails renders expands as collapsible <details> blocks in every style
+func (r *Renderer) SetExpandDetails(enabled bool) {
+	r.details = enabled
+}
+
+// ExpandDetails reports whether expands are rendered as <details> blocks
+func (r *Renderer) ExpandDetails() bool {
+	return r.details
+}
+
+// Render converts a callout to Markdown
+func (r *Renderer) Render(c Callout) string {
+	content := strings.TrimSpace(c.Content)
+	if c.Kind == KindExpand && r.details {
+		return r.renderDetails(c, content)
+	}
+	switch r.style {
+	case StyleGFM:
+		return r.renderGFM(c, content)
+	case StyleMkDocs:
+		return r.renderMkDocs(c, content)
+	case StyleDocusaurus:
+		return r.renderDocusaurus(c, content)
+	case StyleObsidian:
+		return r.renderObsidian(c, content)
+	default:
+		return r.renderBlockquote(c, content)
+	}
+}
+
+// title returns the explicit title, or the label of the kind prefixed by a custom icon
+func (r *Renderer) title(c Callout) string {
+	title := c.Title
+	if title == "" {
+		title = r.labels[c.Kind]
+	}
+	return strings.TrimSpace(c.Icon + " " + title)
+}
+
+// renderBlockquote writes the emoji label format, with any title in bold on the first line.
+// Expands keep only their content.
+func (r *Renderer) renderBlockquote(c Callout, content string) string {
+	if c.Kind == KindExpand {
+		return content
+	}
+	if c.Title != "" {
+		content = strings.TrimSpace("**" + c.Title + "**\n\n" + content)
+	}
+
+	prefix := c.Icon
+	if e, ok := emoji[c.Kind]; ok {
+		prefix = fmt.Sprintf("%s **%s:**", e, r.labels[c.Kind])
+	}
+	return Blockquote(prefix, content)
+}
+
+// renderGFM writes GitHub alerts; titles and untyped containers become bold first lines
+func (r *Renderer) renderGFM(c Callout, content string) string {
+	alert, ok := admonitionTypes[StyleGFM][c.Kind]
+	if !ok {
+		if c.Kind == KindExpand {
+			c = Callout{Kind: KindPanel, Title: r.title(c)}
+		}
+		return r.renderBlockquote(c, content)
+	}
+	body := content
+	if c.Title != "" {
+		body = strings.TrimSpace("**" + c.Title + "**\n\n" + body)
+	}
+	return Blockquote("", "[!"+alert+"]\n"+body)
+}
+
+// renderMkDocs writes admonitions with indented bodies; expands are collapsible
+func (r *Renderer) renderMkDocs(c Callout, content string) string {
+	marker := "!!!"
+	if c.Kind == KindExpand {
+		marker = "???"
+	}
+	header := fmt.Sprintf("%s %s", marker, admonitionTypes[StyleMkDocs][c.Kind])
+	if title := r.title(c); title != "" {
+		header += ` "` + strings.ReplaceAll(title, `"`, `'`) + `"`
+	}
+	if content == "" {
+		return header
+	}
+	return header + "\n\n" + indent(content, "    ")
+}
+
+// renderDocusaurus writes ::: admonitions; expands use the <details> element Docusaurus styles
+func (r *Renderer) renderDocusaurus(c Callout, content string) string {
+	if c.Kind == KindExpand {
+		return r.renderDetails(c, content)
+	}
+	header := ":::" + admonitionTypes[StyleDocusaurus][c.Kind]
+	if title := r.title(c); title != "" {
+		header += "[" + title + "]"
+	}
+	if content == "" {
+		return header + "\n\n:::"
+	}
+	return header + "\n\n" + content + "\n\n:::"
+}
+
+// renderDetails writes a <details> block. The blank lines around the body end the
+// HTML block, so GitHub and GitLab render the Markdown inside it.
+func (r *Renderer) renderDetails(c Callout, content string) string {
+	summary := fmt.Sprintf("<details>\n<summary>%s</summary>", html.EscapeString(r.title(c)))
+	if content == "" {
+		return summary + "\n\n</details>"
+	}
+	return summary + "\n\n" + content + "\n\n</details>"
+}
+
+// renderObsidian writes callouts; expands fold by default
+func (r *Renderer) renderObsidian(c Callout, content string) string {
+	header := "[!" + admonitionTypes[StyleObsidian][c.Kind] + "]"
+	if c.Kind == KindExpand {
+		header += "-"
+	}
+	if title := r.title(c); title != "" {
+		header += " " + title
+	}
+	return Blockquote("", header+"\n"+content)
+}
+
+// Blockquote renders content as a blockquote, starting with prefix when it is set
+func Blockquote(prefix, content string) string {
+	if prefix == "" {
+		return quoteLines(content)
+	}
+	if content == "" {
+		return "> " + prefix
+	}
+	if !strings.Contains(content, "\n") {
+		return fmt.Sprintf("> %s %s", prefix, content)
+	}
+	return "> " + prefix + "\n" + quoteLines(content)
+}
+
+// quoteLines prefixes every line with a blockquote marker, collapsing runs of blank lines
+func quoteLines(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, "> "+line)
+		} else if len(lines) > 0 && lines[len(lines)-1] != ">" {
+			lines = append(lines, ">")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent prefixes every non-blank line
+func indent(content, prefix string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		} else {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
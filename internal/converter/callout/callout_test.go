@@ -0,0 +1,101 @@
+package callout
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	info := Callout{Kind: KindInfo, Content: "Heads up"}
+	titled := Callout{Kind: KindWarning, Title: "Before you start", Content: "Back up first.\n\nThen migrate."}
+	panel := Callout{Kind: KindPanel, Title: "Checklist", Content: "- one"}
+	custom := Callout{Kind: KindPanel, Icon: "🚀", Content: "Launch"}
+	expand := Callout{Kind: KindExpand, Title: "Logs", Content: "line"}
+
+	tests := []struct {
+		style Style
+		in    Callout
+		want  string
+	}{
+		{StyleBlockquote, info, "> ℹ️ **Info:** Heads up"},
+		{StyleBlockquote, titled, "> ⚠️ **Warning:**\n> **Before you start**\n>\n> Back up first.\n>\n> Then migrate."},
+		{StyleBlockquote, panel, "> **Checklist**\n>\n> - one"},
+		{StyleBlockquote, custom, "> 🚀 Launch"},
+		{StyleBlockquote, expand, "line"},
+
+		{StyleGFM, info, "> [!NOTE]\n> Heads up"},
+		{StyleGFM, titled, "> [!WARNING]\n> **Before you start**\n>\n> Back up first.\n>\n> Then migrate."},
+		{StyleGFM, Callout{Kind: KindError, Content: "Broken"}, "> [!CAUTION]\n> Broken"},
+		{StyleGFM, panel, "> **Checklist**\n>\n> - one"},
+		{StyleGFM, expand, "> **Logs**\n>\n> line"},
+
+		{StyleMkDocs, info, "!!! info \"Info\"\n\n    Heads up"},
+		{StyleMkDocs, titled, "!!! warning \"Before you start\"\n\n    Back up first.\n\n    Then migrate."},
+		{StyleMkDocs, custom, "!!! note \"🚀\"\n\n    Launch"},
+		{StyleMkDocs, expand, "??? note \"Logs\"\n\n    line"},
+
+		{StyleDocusaurus, info, ":::info[Info]\n\nHeads up\n\n:::"},
+		{StyleDocusaurus, Callout{Kind: KindSuccess, Content: "Shipped"}, ":::tip[Success]\n\nShipped\n\n:::"},
+		{StyleDocusaurus, expand, "<details>\n<summary>Logs</summary>\n\nline\n\n</details>"},
+
+		{StyleObsidian, info, "> [!info] Info\n> Heads up"},
+		{StyleObsidian, panel, "> [!note] Checklist\n> - one"},
+		{StyleObsidian, Callout{Kind: KindExpand, Content: "line"}, "> [!note]- Click here to expand...\n> line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style)+"/"+string(tt.in.Kind), func(t *testing.T) {
+			if got := New(tt.style, nil).Render(tt.in); got != tt.want {
+				t.Fatalf("unexpected output:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizedLabels(t *testing.T) {
+	labels, err := ParseLabels(map[string]string{"Info": "Hinweis", "expand": "Details anzeigen"})
+	if err != nil {
+		t.Fatalf("ParseLabels returned error: %v", err)
+	}
+
+	if got := New(StyleBlockquote, labels).Render(Callout{Kind: KindInfo, Content: "Text"}); got != "> ℹ️ **Hinweis:** Text" {
+		t.Errorf("blockquote: got %q", got)
+	}
+	if got := New(StyleMkDocs, labels).Render(Callout{Kind: KindExpand, Content: "Text"}); got != "??? note \"Details anzeigen\"\n\n    Text" {
+		t.Errorf("mkdocs: got %q", got)
+	}
+	if got := New(StyleObsidian, labels).Render(Callout{Kind: KindWarning, Content: "Text"}); got != "> [!warning] Warning\n> Text" {
+		t.Errorf("unlocalized kinds keep the default label, got %q", got)
+	}
+
+	if _, err := ParseLabels(map[string]string{"caution": "Vorsicht"}); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	if style, err := ParseStyle(""); err != nil || style != StyleBlockquote {
+		t.Fatalf("expected blockquote default, got %q, %v", style, err)
+	}
+	if style, err := ParseStyle("MkDocs"); err != nil || style != StyleMkDocs {
+		t.Fatalf("expected mkdocs, got %q, %v", style, err)
+	}
+	if _, err := ParseStyle("asciidoc"); err == nil {
+		t.Fatal("expected error for unknown style")
+	}
+}
+
+func TestExpandDetails(t *testing.T) {
+	r := New(StyleGFM, nil)
+	r.SetExpandDetails(true)
+
+	inner := r.Render(Callout{Kind: KindExpand, Content: "- nested"})
+	outer := r.Render(Callout{Kind: KindExpand, Title: "Logs <stderr>", Content: "First\n\n" + inner})
+
+	want := "<details>\n<summary>Logs &lt;stderr&gt;</summary>\n\nFirst\n\n<details>\n<summary>Click here to expand...</summary>\n\n- nested\n\n</details>\n\n</details>"
+	if outer != want {
+		t.Fatalf("unexpected output:\n got: %q\nwant: %q", outer, want)
+	}
+
+	// Other callouts keep the style's syntax
+	if got := r.Render(Callout{Kind: KindTip, Content: "Hint"}); got != "> [!TIP]\n> Hint" {
+		t.Fatalf("unexpected tip: %q", got)
+	}
+}
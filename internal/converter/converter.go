@@ -10,21 +10,36 @@ import (
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/callout"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
 	"github.com/jackchuka/confluence-md/internal/converter/model"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin/attachments"
+	"github.com/jackchuka/confluence-md/internal/jira"
 )
 
 const maxImageSizeBytes = 10 * 1024 * 1024
 
 // Converter handles HTML to Markdown conversion
 type Converter struct {
-	mdConverter *converter.Converter
-	plugin      *plugin.ConfluencePlugin
-	attachments attachments.Resolver
+	mdConverter   *converter.Converter
+	viewConverter *converter.Converter
+	plugin        *plugin.ConfluencePlugin
+	viewPlugin    *plugin.ViewPlugin
+	attachments   attachments.Resolver
 
 	// options
-	imageFolder string
+	imageFolder    string
+	resolver       attachments.Resolver
+	headingIDs     plugin.HeadingIDStyle
+	jira           jira.Config
+	callouts       *callout.Renderer
+	tocPlaceholder bool
+	textDiagrams   map[string]string
+	mathStyle      plugin.MathStyle
+	codeTitles     plugin.CodeTitleStyle
+	layoutStyle    layout.Style
+	mediaEmbeds    bool
 }
 
 type Option func(*Converter)
@@ -35,6 +50,84 @@ func WithDownloadAttachments(imageFolder string) Option {
 	}
 }
 
+// WithAttachmentResolver sets the source for attachment content, such as a local
+// directory for offline conversion. It defaults to the Confluence client.
+func WithAttachmentResolver(resolver attachments.Resolver) Option {
+	return func(c *Converter) {
+		c.resolver = resolver
+	}
+}
+
+// WithHeadingIDs selects how heading and anchor IDs are generated. It defaults to
+// Confluence's own scheme, so fragments in Confluence URLs keep working.
+func WithHeadingIDs(style plugin.HeadingIDStyle) Option {
+	return func(c *Converter) {
+		c.headingIDs = style
+	}
+}
+
+// WithJira sets the Jira sites that jira macros link to, and an optional provider
+// of issue summaries and statuses
+func WithJira(cfg jira.Config) Option {
+	return func(c *Converter) {
+		c.jira = cfg
+	}
+}
+
+// WithCallouts selects the syntax for callout macros, panels and expands
+func WithCallouts(r *callout.Renderer) Option {
+	return func(c *Converter) {
+		c.callouts = r
+	}
+}
+
+// WithTOCPlaceholder writes toc macros as a placeholder comment instead of a list of
+// links to the page's headings, for renderers that build their own table of contents
+func WithTOCPlaceholder(enabled bool) Option {
+	return func(c *Converter) {
+		c.tocPlaceholder = enabled
+	}
+}
+
+// WithTextDiagrams maps additional diagram macros to the code fence language their
+// source is written out with, e.g. {"kroki": "plantuml"}. An empty language drops
+// one of the default mappings.
+func WithTextDiagrams(languages map[string]string) Option {
+	return func(c *Converter) {
+		c.textDiagrams = languages
+	}
+}
+
+// WithMathStyle selects the delimiters for math macros. It defaults to $...$ and $$...$$.
+func WithMathStyle(style plugin.MathStyle) Option {
+	return func(c *Converter) {
+		c.mathStyle = style
+	}
+}
+
+// WithCodeTitles selects how code block titles are written. It defaults to a bold caption.
+func WithCodeTitles(style plugin.CodeTitleStyle) Option {
+	return func(c *Converter) {
+		c.codeTitles = style
+	}
+}
+
+// WithLayoutStyle selects how the columns of page layouts and section macros are
+// written. It defaults to one column after another in reading order.
+func WithLayoutStyle(style layout.Style) Option {
+	return func(c *Converter) {
+		c.layoutStyle = style
+	}
+}
+
+// WithMediaEmbeds writes multimedia and widget macros as HTML5 <video> and <iframe>
+// embeds instead of links
+func WithMediaEmbeds(enabled bool) Option {
+	return func(c *Converter) {
+		c.mediaEmbeds = enabled
+	}
+}
+
 // NewConverter creates a new HTML to Markdown converter
 func NewConverter(client confluence.Client, opts ...Option) *Converter {
 	c := &Converter{}
@@ -45,18 +138,36 @@ func NewConverter(client confluence.Client, opts ...Option) *Converter {
 		}
 	}
 
-	var resolver attachments.Resolver
-	if client != nil {
+	resolver := c.resolver
+	if resolver == nil && client != nil {
 		resolver = attachments.NewService(client)
-		if c.imageFolder != "" {
-			c.attachments = resolver
-		}
+	}
+	if resolver != nil && c.imageFolder != "" {
+		c.attachments = resolver
+	}
+
+	if client != nil {
 		// Use the client-aware plugin constructor for user resolution
 		c.plugin = plugin.NewConfluencePluginWithClient(client, resolver, c.imageFolder)
 	} else {
 		// Use the basic plugin constructor when no client available
 		c.plugin = plugin.NewConfluencePlugin(resolver, c.imageFolder)
 	}
+	if c.headingIDs != "" {
+		c.plugin.SetHeadingIDStyle(c.headingIDs)
+	}
+	c.plugin.SetJira(c.jira)
+	c.plugin.SetTOCPlaceholder(c.tocPlaceholder)
+	c.plugin.SetBodyPreprocessor(c.preprocessCDATA)
+	c.plugin.SetTextDiagrams(c.textDiagrams)
+	c.plugin.SetMathStyle(c.mathStyle)
+	c.plugin.SetCodeTitleStyle(c.codeTitles)
+	c.plugin.SetLayoutStyle(c.layoutStyle)
+	c.plugin.SetMediaEmbeds(c.mediaEmbeds)
+	if c.callouts == nil {
+		c.callouts = callout.New(callout.StyleBlockquote, nil)
+	}
+	c.plugin.SetCallouts(c.callouts)
 	conv := converter.NewConverter(
 		converter.WithPlugins(
 			base.NewBasePlugin(),
@@ -68,6 +179,20 @@ func NewConverter(client confluence.Client, opts ...Option) *Converter {
 	)
 	c.mdConverter = conv
 
+	// View markup from HTML exports shares tables and inline elements with storage format
+	c.viewPlugin = plugin.NewViewPlugin(c.imageFolder)
+	c.viewPlugin.SetCallouts(c.callouts)
+	c.viewPlugin.SetCodeTitleStyle(c.codeTitles)
+	c.viewPlugin.SetLayoutStyle(c.layoutStyle)
+	c.viewConverter = converter.NewConverter(
+		converter.WithPlugins(
+			base.NewBasePlugin(),
+			commonmark.NewCommonmarkPlugin(),
+			c.plugin,
+			c.viewPlugin,
+		),
+	)
+
 	return c
 }
 
@@ -76,6 +201,12 @@ func (c *Converter) ConvertHTML(html string) (string, error) {
 	return c.convertHtml(html)
 }
 
+// ConvertADF converts an Atlassian Document Format JSON document to Markdown
+func (c *Converter) ConvertADF(doc string) (string, error) {
+	markdown, _, err := c.convertADF(doc, nil)
+	return markdown, err
+}
+
 // ConvertPage converts a Confluence page to Markdown
 func (c *Converter) ConvertPage(
 	page *confluenceModel.ConfluencePage,
@@ -86,6 +217,8 @@ func (c *Converter) ConvertPage(
 		return nil, fmt.Errorf("invalid page: %w", err)
 	}
 	c.plugin.SetCurrentPage(page)
+	c.plugin.SetBaseURL(baseURL)
+	c.viewPlugin.SetCurrentPage(page)
 
 	// Create markdown document
 	doc, err := model.NewMarkdownDocument(page, baseURL)
@@ -93,21 +226,45 @@ func (c *Converter) ConvertPage(
 		return nil, fmt.Errorf("failed to create markdown document: %w", err)
 	}
 
-	htmlContent := page.Content.Storage.Value
+	body := page.Content.Storage.Value
 
-	markdown, err := c.convertHtml(htmlContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert HTML to Markdown: %w", err)
+	switch page.Content.Storage.Representation {
+	case confluenceModel.RepresentationADF:
+		markdown, renderer, err := c.convertADF(body, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert ADF to Markdown: %w", err)
+		}
+		doc.Content = markdown
+		doc.Frontmatter.Description = renderer.Excerpt()
+		doc.Tasks = renderer.Tasks()
+		doc.Images = buildImageRefs(renderer.Images(), doc.Frontmatter.Confluence.PageID, baseURL)
+	case confluenceModel.RepresentationView:
+		markdown, err := c.convertView(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert view HTML to Markdown: %w", err)
+		}
+		doc.Content = markdown
+		doc.Tasks = c.viewPlugin.Tasks()
+		doc.Images = buildImageRefs(c.viewPlugin.ReferencedAttachments(), doc.Frontmatter.Confluence.PageID, baseURL)
+	default:
+		markdown, err := c.convertHtml(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert HTML to Markdown: %w", err)
+		}
+		doc.Content = markdown
+		doc.Frontmatter.Description = c.plugin.Excerpt()
+		doc.Tasks = c.plugin.Tasks()
+		// Extract image references for downloading
+		doc.Images = c.extractImageReferences(body, doc.Frontmatter.Confluence.PageID, baseURL)
 	}
-	doc.Content = markdown
-	// Extract image references for downloading
-	imageRefs := c.extractImageReferences(htmlContent, doc.Frontmatter.Confluence.PageID, baseURL)
-	doc.Images = imageRefs
 
 	if c.attachments != nil {
 		if err := c.downloadImages(doc, page, outputDir); err != nil {
 			return nil, fmt.Errorf("failed to download images: %w", err)
 		}
+		if err := c.saveAssets(doc, outputDir); err != nil {
+			return nil, fmt.Errorf("failed to save assets: %w", err)
+		}
 	}
 
 	return doc, nil
@@ -154,3 +311,19 @@ func (c *Converter) downloadImages(doc *model.MarkdownDocument, page *confluence
 
 	return nil
 }
+
+// saveAssets writes the files fetched by macros, such as diagram sources and previews, to the image folder
+func (c *Converter) saveAssets(doc *model.MarkdownDocument, outputDir string) error {
+	for _, asset := range c.plugin.Assets() {
+		filePath := filepath.Join(outputDir, c.imageFolder, asset.FileName)
+		fmt.Println("Saving asset:", asset.FileName, "to", filePath)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create image directory: %w", err)
+		}
+		if err := os.WriteFile(filePath, asset.Data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", asset.FileName, err)
+		}
+		doc.Images = append(doc.Images, model.ImageRef{FileName: asset.FileName, Size: int64(len(asset.Data))})
+	}
+	return nil
+}
@@ -1,14 +1,17 @@
 package converter
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
 	confModel "github.com/jackchuka/confluence-md/internal/confluence/model"
 	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
 	mock_attachments "github.com/jackchuka/confluence-md/internal/converter/plugin/attachments/mock"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -78,6 +81,98 @@ func TestConverterConvertPage(t *testing.T) {
 	}
 }
 
+func TestConverterIncludesAndExcerpt(t *testing.T) {
+	snippet := &confModel.ConfluencePage{
+		ID:       "20",
+		Title:    "Snippet",
+		SpaceKey: "SPACE",
+		Content: confModel.ConfluenceContent{Storage: confModel.ContentStorage{
+			Value:          `<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">bash</ac:parameter><ac:plain-text-body><![CDATA[echo "<ok>"]]></ac:plain-text-body></ac:structured-macro>`,
+			Representation: "storage",
+		}},
+	}
+
+	ctrl := gomock.NewController(t)
+	client := mock_confluence.NewMockClient(ctrl)
+	client.EXPECT().FindContent(confModel.ContentRef{Type: confModel.ContentTypePage, SpaceKey: "SPACE", Title: "Snippet"}).Return(snippet, nil)
+	client.EXPECT().GetPage("20").Return(snippet, nil)
+
+	page := &confModel.ConfluencePage{
+		ID:       "123",
+		Title:    "Sample Page",
+		SpaceKey: "SPACE",
+		Content: confModel.ConfluenceContent{Storage: confModel.ContentStorage{
+			Value:          `<ac:structured-macro ac:name="excerpt"><ac:parameter ac:name="hidden">true</ac:parameter><ac:rich-text-body><p>How to deploy.</p></ac:rich-text-body></ac:structured-macro><ac:structured-macro ac:name="include"><ac:parameter ac:name=""><ac:link><ri:page ri:content-title="Snippet" /></ac:link></ac:parameter></ac:structured-macro>`,
+			Representation: "storage",
+		}},
+	}
+
+	doc, err := NewConverter(client).ConvertPage(page, "https://example.atlassian.net", ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "```bash\necho \"<ok>\"\n```"; !strings.Contains(doc.Content, want) {
+		t.Fatalf("expected included code block %q, got %q", want, doc.Content)
+	}
+	if doc.Frontmatter.Description != "How to deploy." {
+		t.Fatalf("unexpected description: %q", doc.Frontmatter.Description)
+	}
+}
+
+func TestConverterConvertPageADF(t *testing.T) {
+	conv := NewConverter(nil, WithDownloadAttachments("assets"))
+
+	page := &confModel.ConfluencePage{
+		ID:       "123",
+		Title:    "ADF Page",
+		SpaceKey: "SPACE",
+		Content: confModel.ConfluenceContent{
+			Storage: confModel.ContentStorage{
+				Value:          `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Hello ADF"}]},{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"f1"}}]}]}`,
+				Representation: confModel.RepresentationADF,
+			},
+		},
+		Attachments: []confModel.ConfluenceAttachment{{
+			ID:           "att1",
+			Title:        "chart.png",
+			MediaType:    "image/png",
+			FileSize:     10,
+			DownloadLink: "/download/attachments/123/chart.png",
+			FileID:       "f1",
+		}},
+	}
+
+	doc, err := conv.ConvertPage(page, "https://example.atlassian.net", ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Hello ADF\n\n![chart.png](assets%2Fchart.png)"
+	if doc.Content != want {
+		t.Fatalf("unexpected content: %q", doc.Content)
+	}
+	if len(doc.Images) != 1 || doc.Images[0].FileName != "chart.png" {
+		t.Fatalf("unexpected images: %#v", doc.Images)
+	}
+}
+
+func TestConverterConvertPageADFFromV1(t *testing.T) {
+	response := `{"id":"123","title":"ADF Page","space":{"key":"SPACE"},` +
+		`"body":{"atlas_doc_format":{"value":"{\"type\":\"doc\",\"version\":1,\"content\":[{\"type\":\"mediaSingle\",\"content\":[{\"type\":\"media\",\"attrs\":{\"type\":\"file\",\"id\":\"f1\"}}]}]}","representation":"atlas_doc_format"}},` +
+		`"children":{"attachment":{"results":[{"id":"att1","title":"chart.png","extensions":{"mediaType":"image/png","fileSize":10,"fileId":"f1"},"_links":{"download":"/download/attachments/123/chart.png"}}]}}}`
+	var apiPage confModel.ConfluenceAPIPage
+	if err := json.Unmarshal([]byte(response), &apiPage); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	doc, err := NewConverter(nil, WithDownloadAttachments("assets")).ConvertPage(confModel.ConvertAPIPageToModel(&apiPage), "https://example.atlassian.net", ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Content != "![chart.png](assets%2Fchart.png)" {
+		t.Fatalf("unexpected content: %q", doc.Content)
+	}
+}
+
 func TestConverterDownloadImages(t *testing.T) {
 	data := []byte("image-bytes")
 	attachment := &confModel.ConfluenceAttachment{Title: "diagram.png", MediaType: "image/png", FileSize: int64(len(data))}
@@ -124,6 +219,126 @@ func TestConverterDownloadImages(t *testing.T) {
 	}
 }
 
+func TestConverterSavesDiagramAssets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	resolver := mock_attachments.NewMockResolver(ctrl)
+	resolver.EXPECT().DownloadAttachment(gomock.Any(), "Architecture", 0).
+		Return(&confModel.ConfluenceAttachment{Title: "Architecture"}, []byte("<mxfile/>"), nil)
+	resolver.EXPECT().DownloadAttachment(gomock.Any(), "Architecture.png", 0).
+		Return(&confModel.ConfluenceAttachment{Title: "Architecture.png"}, []byte("png"), nil)
+
+	conv := NewConverter(nil, WithDownloadAttachments("assets"), WithAttachmentResolver(resolver))
+	page := &confModel.ConfluencePage{
+		ID:       "123",
+		Title:    "Diagrams",
+		SpaceKey: "SPACE",
+		Content: confModel.ConfluenceContent{Storage: confModel.ContentStorage{
+			Value:          `<ac:structured-macro ac:name="drawio"><ac:parameter ac:name="diagramName">Architecture</ac:parameter></ac:structured-macro>`,
+			Representation: "storage",
+		}},
+	}
+
+	dir := t.TempDir()
+	doc, err := conv.ConvertPage(page, "https://example.atlassian.net", dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.Images) != 2 {
+		t.Fatalf("unexpected images: %#v", doc.Images)
+	}
+	for name, want := range map[string]string{"Architecture.drawio": "<mxfile/>", "Architecture.png": "png"} {
+		data, err := os.ReadFile(filepath.Join(dir, "assets", name))
+		if err != nil {
+			t.Fatalf("expected %s to be saved: %v", name, err)
+		}
+		if string(data) != want {
+			t.Fatalf("unexpected %s content: %q", name, data)
+		}
+	}
+}
+
+func TestConverterTextDiagrams(t *testing.T) {
+	conv := NewConverter(nil, WithTextDiagrams(map[string]string{"kroki-ditaa": "ditaa", "mermaid-macro": ""}))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "plantuml body",
+			html: `<ac:structured-macro ac:name="plantuml"><ac:plain-text-body><![CDATA[@startuml
+Alice -> Bob: "hi" <b>
+@enduml]]></ac:plain-text-body></ac:structured-macro>`,
+			want: "```plantuml\n@startuml\nAlice -> Bob: \"hi\" <b>\n@enduml\n```",
+		},
+		{
+			name: "graphviz body",
+			html: `<ac:structured-macro ac:name="graphviz"><ac:plain-text-body><![CDATA[digraph { a -> b }]]></ac:plain-text-body></ac:structured-macro>`,
+			want: "```dot\ndigraph { a -> b }\n```",
+		},
+		{
+			name: "custom macro",
+			html: `<ac:structured-macro ac:name="kroki-ditaa"><ac:plain-text-body><![CDATA[+--+]]></ac:plain-text-body></ac:structured-macro>`,
+			want: "```ditaa\n+--+\n```",
+		},
+		{
+			name: "removed default",
+			html: `<ac:structured-macro ac:name="mermaid-macro"><ac:plain-text-body><![CDATA[graph TD]]></ac:plain-text-body></ac:structured-macro>`,
+			want: "<!-- Unsupported macro: mermaid-macro -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertHTML(tt.html)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverterMathMacros(t *testing.T) {
+	inline := `<p>Energy <ac:structured-macro ac:name="mathinline"><ac:parameter ac:name="body">E = mc^2</ac:parameter></ac:structured-macro> holds.</p>`
+	block := `<ac:structured-macro ac:name="mathblock"><ac:plain-text-body><![CDATA[\sum_{i=1}^{n} x_i < \infty]]></ac:plain-text-body></ac:structured-macro>`
+
+	tests := []struct {
+		name  string
+		style plugin.MathStyle
+		html  string
+		want  string
+	}{
+		{name: "dollar inline", style: plugin.MathDollar, html: inline, want: "Energy $E = mc^2$ holds."},
+		{name: "dollar block", style: plugin.MathDollar, html: block, want: "$$\n\\sum_{i=1}^{n} x_i < \\infty\n$$"},
+		{name: "latex inline", style: plugin.MathLaTeX, html: inline, want: "Energy \\(E = mc^2\\) holds."},
+		{name: "latex block", style: plugin.MathLaTeX, html: block, want: "\\[\n\\sum_{i=1}^{n} x_i < \\infty\n\\]"},
+		{name: "gfm inline", style: plugin.MathGFM, html: inline, want: "Energy $`E = mc^2`$ holds."},
+		{name: "gfm block", style: plugin.MathGFM, html: block, want: "```math\n\\sum_{i=1}^{n} x_i < \\infty\n```"},
+		{
+			name:  "latex macro body parameter",
+			style: plugin.MathDollar,
+			html:  `<ac:structured-macro ac:name="latex"><ac:parameter ac:name="body">a^2 + b^2 = c^2</ac:parameter></ac:structured-macro>`,
+			want:  "$$\na^2 + b^2 = c^2\n$$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewConverter(nil, WithMathStyle(tt.style)).ConvertHTML(tt.html)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSaveMarkdownDocument(t *testing.T) {
 	tmpDir := t.TempDir()
 	doc := &convModel.MarkdownDocument{
@@ -233,6 +448,11 @@ func TestFixMarkdownLinks(t *testing.T) {
 	if got := fixMarkdownLinks(input); got != want {
 		t.Fatalf("fixMarkdownLinks(%q) = %q, want %q", input, got, want)
 	}
+	input = "See [Section](/wiki/spaces/SPACE/pages/12345/Some-Page#Some-Page-Setup)"
+	want = "See [Section](confluence://pageId/12345#Some-Page-Setup)"
+	if got := fixMarkdownLinks(input); got != want {
+		t.Fatalf("fixMarkdownLinks(%q) = %q, want %q", input, got, want)
+	}
 }
 
 func TestFixNestedListSpacing(t *testing.T) {
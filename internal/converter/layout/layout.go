@@ -0,0 +1,100 @@
+// Package layout renders multi-column Confluence page layouts and section macros,
+// which Markdown has no syntax for, either linearized or as an HTML grid
+package layout
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style selects how the columns of a layout section are written
+type Style string
+
+const (
+	StyleLinear    Style = "linear"    // Columns one after another in reading order
+	StyleSeparated Style = "separated" // Linear, with a <!-- column --> comment between columns
+	StyleGrid      Style = "grid"      // <div style="display:flex"> with a div per column
+)
+
+// Separator is written between columns in the separated style
+const Separator = "<!-- column -->"
+
+// Column is one converted column of a layout section
+type Column struct {
+	Content string // Converted Markdown
+	Width   string // CSS width, such as 30% or 200px; empty for an equal share
+}
+
+// sectionWidths gives the column widths of layout section types with unequal columns
+var sectionWidths = map[string][]string{
+	"two_left_sidebar":     {"33%", "67%"},
+	"two_right_sidebar":    {"67%", "33%"},
+	"three_with_sidebars":  {"25%", "50%", "25%"},
+	"three_left_sidebars":  {"25%", "25%", "50%"},
+	"three_right_sidebars": {"50%", "25%", "25%"},
+}
+
+// ParseStyle validates a layout style name
+func ParseStyle(s string) (Style, error) {
+	switch style := Style(strings.ToLower(strings.TrimSpace(s))); style {
+	case StyleLinear, StyleSeparated, StyleGrid:
+		return style, nil
+	case "":
+		return StyleLinear, nil
+	default:
+		return "", fmt.Errorf("unsupported layout style: %s (use linear, separated or grid)", s)
+	}
+}
+
+// SectionWidths returns the column widths of a layout section type, such as
+// two_left_sidebar or two-left-sidebar, or nil when its columns are equal
+func SectionWidths(sectionType string, columns int) []string {
+	widths := sectionWidths[strings.ReplaceAll(sectionType, "-", "_")]
+	if len(widths) != columns {
+		return nil
+	}
+	return widths
+}
+
+// Render writes the columns of one layout section. A section with a single
+// column is written as its content in every style.
+func Render(style Style, columns []Column) string {
+	if len(columns) == 1 {
+		return strings.TrimSpace(columns[0].Content)
+	}
+	if style == StyleGrid {
+		return renderGrid(columns)
+	}
+
+	separator := "\n\n"
+	if style == StyleSeparated {
+		separator = "\n\n" + Separator + "\n\n"
+	}
+	var parts []string
+	for _, column := range columns {
+		if content := strings.TrimSpace(column.Content); content != "" {
+			parts = append(parts, content)
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+// renderGrid writes a flex row, leaving blank lines around each column's content
+// so Markdown inside the divs is still converted
+func renderGrid(columns []Column) string {
+	var b strings.Builder
+	b.WriteString(`<div style="display:flex">` + "\n")
+	for _, column := range columns {
+		flex := "1"
+		if column.Width != "" {
+			flex = "0 0 " + column.Width
+		}
+		fmt.Fprintf(&b, "<div style=\"flex:%s\">\n", flex)
+		if content := strings.TrimSpace(column.Content); content != "" {
+			b.WriteString("\n" + content + "\n\n")
+		}
+		b.WriteString("</div>\n")
+	}
+	b.WriteString("</div>")
+	return b.String()
+}
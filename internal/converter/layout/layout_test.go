@@ -0,0 +1,64 @@
+package layout
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	columns := []Column{
+		{Content: "## Left\n\none", Width: "33%"},
+		{Content: "  "},
+		{Content: "two\n"},
+	}
+
+	tests := []struct {
+		name    string
+		style   Style
+		columns []Column
+		want    string
+	}{
+		{name: "linear", style: StyleLinear, columns: columns, want: "## Left\n\none\n\ntwo"},
+		{name: "separated", style: StyleSeparated, columns: columns, want: "## Left\n\none\n\n<!-- column -->\n\ntwo"},
+		{
+			name:    "grid",
+			style:   StyleGrid,
+			columns: columns,
+			want: "<div style=\"display:flex\">\n" +
+				"<div style=\"flex:0 0 33%\">\n\n## Left\n\none\n\n</div>\n" +
+				"<div style=\"flex:1\">\n</div>\n" +
+				"<div style=\"flex:1\">\n\ntwo\n\n</div>\n" +
+				"</div>",
+		},
+		{name: "single column", style: StyleGrid, columns: []Column{{Content: "only\n", Width: "100%"}}, want: "only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.style, tt.columns); got != tt.want {
+				t.Fatalf("unexpected output:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionWidths(t *testing.T) {
+	if got := SectionWidths("two-left-sidebar", 2); len(got) != 2 || got[0] != "33%" || got[1] != "67%" {
+		t.Fatalf("unexpected widths: %v", got)
+	}
+	if got := SectionWidths("two_equal", 2); got != nil {
+		t.Fatalf("equal columns should have no widths, got %v", got)
+	}
+	if got := SectionWidths("three_with_sidebars", 2); got != nil {
+		t.Fatalf("mismatched column count should have no widths, got %v", got)
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	if style, err := ParseStyle(""); err != nil || style != StyleLinear {
+		t.Fatalf("ParseStyle(\"\") = %q, %v", style, err)
+	}
+	if style, err := ParseStyle("Grid"); err != nil || style != StyleGrid {
+		t.Fatalf("ParseStyle(Grid) = %q, %v", style, err)
+	}
+	if _, err := ParseStyle("table"); err == nil {
+		t.Fatal("expected error for unsupported style")
+	}
+}
@@ -0,0 +1,173 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LinkPolicy controls how links to pages outside the converted set are written
+type LinkPolicy string
+
+const (
+	LinkPolicyURL        LinkPolicy = "url"        // Absolute Confluence URL
+	LinkPolicyConfluence LinkPolicy = "confluence" // confluence://pageId/<id> reference
+	LinkPolicyText       LinkPolicy = "text"       // Link text only
+)
+
+// ParseLinkPolicy validates a link policy name
+func ParseLinkPolicy(name string) (LinkPolicy, error) {
+	switch policy := LinkPolicy(strings.ToLower(name)); policy {
+	case LinkPolicyURL, LinkPolicyConfluence, LinkPolicyText:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported link policy %q (expected url, confluence or text)", name)
+	}
+}
+
+// pageLinkRegex matches Markdown links and captures the link text, which may
+// contain escaped brackets, and target
+var pageLinkRegex = regexp.MustCompile(`(!?)\[((?:\\.|[^\]\\])*)\]\(([^)\s]+)\)`)
+
+// LinkIndex maps page IDs to the Markdown files they were written to, so links
+// between converted pages can be rewritten as relative paths.
+type LinkIndex struct {
+	paths   map[string]string
+	order   []string
+	baseURL string
+	policy  LinkPolicy
+
+	pageURLRegex *regexp.Regexp
+}
+
+// NewLinkIndex creates an index for pages converted from the site at baseURL
+func NewLinkIndex(baseURL string, policy LinkPolicy) *LinkIndex {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	index := &LinkIndex{
+		paths:   make(map[string]string),
+		baseURL: baseURL,
+		policy:  policy,
+	}
+	if baseURL != "" {
+		index.pageURLRegex = regexp.MustCompile(`^` + regexp.QuoteMeta(baseURL) +
+			`/wiki/(?:spaces/[^/]+/pages/(\d+)(?:/[^#]*)?|pages/viewpage\.action\?pageId=(\d+)[^#]*)(#.*)?$`)
+	}
+	return index
+}
+
+// Add records the output path of a converted page
+func (i *LinkIndex) Add(pageID, outputPath string) {
+	if _, ok := i.paths[pageID]; !ok {
+		i.order = append(i.order, pageID)
+	}
+	i.paths[pageID] = outputPath
+}
+
+// RewriteLinks rewrites page links in Markdown written to fromPath. Links to indexed
+// pages become relative paths; other page links follow the link policy.
+func (i *LinkIndex) RewriteLinks(markdown, fromPath string) string {
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	for n, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[n] = pageLinkRegex.ReplaceAllStringFunc(line, func(match string) string {
+			parts := pageLinkRegex.FindStringSubmatch(match)
+			if parts[1] != "" {
+				return match // images never point at pages
+			}
+			return i.rewriteLink(match, parts[2], parts[3], fromPath)
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RewriteFiles rewrites the links in every indexed file on disk
+func (i *LinkIndex) RewriteFiles() error {
+	for _, id := range i.order {
+		path := i.paths[id]
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		rewritten := i.RewriteLinks(string(data), path)
+		if rewritten == string(data) {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(rewritten), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func (i *LinkIndex) rewriteLink(match, text, target, fromPath string) string {
+	pageID, fragment, ok := i.parsePageLink(target)
+	if !ok {
+		return match
+	}
+
+	if path, ok := i.paths[pageID]; ok {
+		return fmt.Sprintf("[%s](%s)", text, relativeLink(fromPath, path, fragment))
+	}
+
+	switch {
+	case i.policy == LinkPolicyText:
+		return text
+	case i.policy == LinkPolicyURL && i.baseURL != "":
+		return fmt.Sprintf("[%s](%s/wiki/pages/viewpage.action?pageId=%s%s)", text, i.baseURL, pageID, fragment)
+	default:
+		return fmt.Sprintf("[%s](confluence://pageId/%s%s)", text, pageID, fragment)
+	}
+}
+
+// parsePageLink extracts the page ID and fragment from confluence:// and site page links
+func (i *LinkIndex) parsePageLink(target string) (string, string, bool) {
+	if rest, ok := strings.CutPrefix(target, "confluence://pageId/"); ok {
+		id, fragment, _ := strings.Cut(rest, "#")
+		if fragment != "" {
+			fragment = "#" + fragment
+		}
+		return id, fragment, id != ""
+	}
+
+	if i.pageURLRegex == nil {
+		return "", "", false
+	}
+	matches := i.pageURLRegex.FindStringSubmatch(target)
+	if matches == nil {
+		return "", "", false
+	}
+	id := matches[1]
+	if id == "" {
+		id = matches[2]
+	}
+	return id, matches[3], true
+}
+
+// relativeLink builds a URL-escaped relative path between two output files
+func relativeLink(fromPath, toPath, fragment string) string {
+	if filepath.Clean(fromPath) == filepath.Clean(toPath) && fragment != "" {
+		return fragment
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(fromPath), toPath)
+	if err != nil {
+		rel = toPath
+	}
+
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for n, segment := range segments {
+		segments[n] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/") + fragment
+}
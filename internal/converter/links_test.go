@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLinkIndexRewriteLinks(t *testing.T) {
+	from := filepath.Join("out", "home", "guide.md")
+
+	tests := []struct {
+		name   string
+		policy LinkPolicy
+		input  string
+		want   string
+	}{
+		{
+			name:   "exported page",
+			policy: LinkPolicyURL,
+			input:  "See [Home](confluence://pageId/1) and [Setup](confluence://pageId/3#Install)",
+			want:   "See [Home](../home.md) and [Setup](setup%20notes.md#Install)",
+		},
+		{
+			name:   "absolute site link to exported page",
+			policy: LinkPolicyURL,
+			input:  "[Home](https://example.atlassian.net/wiki/spaces/DOCS/pages/1/Home#Top)",
+			want:   "[Home](../home.md#Top)",
+		},
+		{
+			name:   "self link keeps only the fragment",
+			policy: LinkPolicyURL,
+			input:  "[Below](confluence://pageId/2#Details)",
+			want:   "[Below](#Details)",
+		},
+		{
+			name:   "outside page as URL",
+			policy: LinkPolicyURL,
+			input:  "[Other](confluence://pageId/99#Intro)",
+			want:   "[Other](https://example.atlassian.net/wiki/pages/viewpage.action?pageId=99#Intro)",
+		},
+		{
+			name:   "outside page with brackets in its title",
+			policy: LinkPolicyURL,
+			input:  `[On-call \[rota\]](confluence://pageId/99) and [Home \[old\]](confluence://pageId/1)`,
+			want:   `[On-call \[rota\]](https://example.atlassian.net/wiki/pages/viewpage.action?pageId=99) and [Home \[old\]](../home.md)`,
+		},
+		{
+			name:   "outside page as confluence reference",
+			policy: LinkPolicyConfluence,
+			input:  "[Other](https://example.atlassian.net/wiki/pages/viewpage.action?pageId=99)",
+			want:   "[Other](confluence://pageId/99)",
+		},
+		{
+			name:   "outside page as text",
+			policy: LinkPolicyText,
+			input:  "[Other](confluence://pageId/99)",
+			want:   "Other",
+		},
+		{
+			name:   "other links and code untouched",
+			policy: LinkPolicyText,
+			input:  "[Site](https://other.example.com/wiki/spaces/X/pages/99)\n```\n[Other](confluence://pageId/99)\n```",
+			want:   "[Site](https://other.example.com/wiki/spaces/X/pages/99)\n```\n[Other](confluence://pageId/99)\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := NewLinkIndex("https://example.atlassian.net/", tt.policy)
+			index.Add("1", filepath.Join("out", "home.md"))
+			index.Add("2", from)
+			index.Add("3", filepath.Join("out", "home", "setup notes.md"))
+
+			if got := index.RewriteLinks(tt.input, from); got != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkIndexRewriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home.md")
+	child := filepath.Join(dir, "home", "child.md")
+	if err := os.MkdirAll(filepath.Dir(child), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(home, []byte("[Child](confluence://pageId/2)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(child, []byte("[Home](confluence://pageId/1)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	index := NewLinkIndex("", LinkPolicyURL)
+	index.Add("1", home)
+	index.Add("2", child)
+	if err := index.RewriteFiles(); err != nil {
+		t.Fatalf("rewrite error: %v", err)
+	}
+
+	for path, want := range map[string]string{home: "[Child](home/child.md)", child: "[Home](../home.md)"} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Fatalf("unexpected content in %s: %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestParseLinkPolicy(t *testing.T) {
+	if policy, err := ParseLinkPolicy("Text"); err != nil || policy != LinkPolicyText {
+		t.Fatalf("unexpected policy: %q, %v", policy, err)
+	}
+	if _, err := ParseLinkPolicy("relative"); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
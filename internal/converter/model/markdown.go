@@ -13,16 +13,18 @@ type MarkdownDocument struct {
 	Frontmatter Frontmatter `yaml:",inline"`
 	Content     string      `yaml:"-"`
 	Images      []ImageRef  `yaml:"-"`
+	Tasks       []Task      `yaml:"-"`
 }
 
 // Frontmatter represents YAML frontmatter for the Markdown document
 type Frontmatter struct {
-	Title      string         `yaml:"title"`
-	Author     string         `yaml:"author"`
-	Date       time.Time      `yaml:"date"`
-	Labels     []string       `yaml:"labels,omitempty"`
-	Confluence ConfluenceRef  `yaml:"confluence"`
-	Custom     map[string]any `yaml:",inline,omitempty"`
+	Title       string         `yaml:"title"`
+	Description string         `yaml:"description,omitempty"` // The page's excerpt
+	Author      string         `yaml:"author"`
+	Date        time.Time      `yaml:"date"`
+	Labels      []string       `yaml:"labels,omitempty"`
+	Confluence  ConfluenceRef  `yaml:"confluence"`
+	Custom      map[string]any `yaml:",inline,omitempty"`
 }
 
 // ConfluenceRef contains reference information back to the original Confluence page
@@ -41,12 +43,28 @@ type ImageRef struct {
 	Size        int64  `json:"size"`
 }
 
+// Task is an action item found on the page
+type Task struct {
+	Status   string `json:"status"` // complete or incomplete, as Confluence stores it
+	Text     string `json:"text"`
+	Assignee string `json:"assignee,omitempty"` // Display name of the first mentioned user
+	Due      string `json:"due,omitempty"`      // Date of the first <time> element
+}
+
+// Done reports whether the task is complete
+func (t Task) Done() bool {
+	return t.Status == "complete"
+}
+
 func (md *MarkdownDocument) WithFrontmatter() (string, error) {
 	var builder strings.Builder
 
 	// Write YAML frontmatter
 	builder.WriteString("---\n")
 	fmt.Fprintf(&builder, "title: %q\n", md.Frontmatter.Title)
+	if md.Frontmatter.Description != "" {
+		fmt.Fprintf(&builder, "description: %q\n", md.Frontmatter.Description)
+	}
 	fmt.Fprintf(&builder, "author: %q\n", md.Frontmatter.Author)
 	fmt.Fprintf(&builder, "date: %q\n", md.Frontmatter.Date.Format(time.RFC3339))
 
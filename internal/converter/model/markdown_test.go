@@ -11,10 +11,11 @@ import (
 func TestMarkdownDocumentWithFrontmatter(t *testing.T) {
 	doc := &MarkdownDocument{
 		Frontmatter: Frontmatter{
-			Title:  "Sample",
-			Author: "Author",
-			Date:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
-			Labels: []string{"one", "two"},
+			Title:       "Sample",
+			Description: "Short summary",
+			Author:      "Author",
+			Date:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Labels:      []string{"one", "two"},
 			Confluence: ConfluenceRef{
 				PageID:   "123",
 				SpaceKey: "SPACE",
@@ -33,6 +34,7 @@ func TestMarkdownDocumentWithFrontmatter(t *testing.T) {
 
 	expectations := []string{
 		"title: \"Sample\"",
+		"description: \"Short summary\"",
 		"author: \"Author\"",
 		"date: \"2024-01-02T03:04:05Z\"",
 		"- \"one\"",
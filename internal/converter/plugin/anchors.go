@@ -0,0 +1,178 @@
+package plugin
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	nethtml "golang.org/x/net/html"
+)
+
+// HeadingIDStyle selects how heading and anchor IDs are generated
+type HeadingIDStyle string
+
+const (
+	// HeadingIDsConfluence matches Confluence's own #PageTitle-Heading fragments
+	HeadingIDsConfluence HeadingIDStyle = "confluence"
+	// HeadingIDsGitHub matches the IDs GitHub and most Markdown renderers give headings
+	HeadingIDsGitHub HeadingIDStyle = "github"
+)
+
+// anchorTag is the element anchor targets are rewritten to before rendering
+const anchorTag = "confluence-anchor"
+
+// ParseHeadingIDStyle validates a heading ID style name
+func ParseHeadingIDStyle(s string) (HeadingIDStyle, error) {
+	switch style := HeadingIDStyle(strings.ToLower(strings.TrimSpace(s))); style {
+	case HeadingIDsConfluence, HeadingIDsGitHub:
+		return style, nil
+	case "":
+		return HeadingIDsConfluence, nil
+	default:
+		return "", fmt.Errorf("unsupported heading ID style: %s (use confluence or github)", s)
+	}
+}
+
+// Slugger generates unique heading IDs within one page
+type Slugger struct {
+	style HeadingIDStyle
+	title string
+	seen  map[string]int
+}
+
+// NewSlugger creates a slugger for the page with the given title
+func NewSlugger(style HeadingIDStyle, title string) *Slugger {
+	return &Slugger{style: style, title: title, seen: make(map[string]int)}
+}
+
+// HeadingID returns the ID for a heading, numbering repeats the way the style's renderer does
+func (s *Slugger) HeadingID(text string) string {
+	id := AnchorID(s.style, s.title, text)
+	count := s.seen[id]
+	s.seen[id]++
+	if count == 0 {
+		return id
+	}
+	if s.style == HeadingIDsGitHub {
+		return fmt.Sprintf("%s-%d", id, count)
+	}
+	return fmt.Sprintf("%s.%d", id, count)
+}
+
+// AnchorID returns the fragment for a named anchor or heading on the page with the given title
+func AnchorID(style HeadingIDStyle, title, name string) string {
+	if style == HeadingIDsGitHub {
+		return githubSlug(name)
+	}
+	return compact(title) + "-" + compact(name)
+}
+
+// githubSlug lowercases text, drops punctuation and joins words with hyphens
+func githubSlug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// compact removes whitespace, as Confluence does when building anchor IDs
+func compact(text string) string {
+	return strings.Join(strings.Fields(text), "")
+}
+
+// SetHeadingIDStyle selects how heading and anchor IDs are generated
+func (p *ConfluencePlugin) SetHeadingIDStyle(style HeadingIDStyle) {
+	p.headingIDs = style
+}
+
+// anchorID returns the fragment for a named anchor on the page with the given title
+func (p *ConfluencePlugin) anchorID(title, name string) string {
+	return AnchorID(p.headingIDs, title, name)
+}
+
+// currentTitle returns the title of the page being converted
+func (p *ConfluencePlugin) currentTitle() string {
+	if p.currentPage == nil {
+		return ""
+	}
+	return p.currentPage.Title
+}
+
+// insertAnchors replaces anchor macros and view-format anchor spans with anchor
+// targets and, for Confluence-style IDs, gives each heading an explicit ID
+func (p *ConfluencePlugin) insertAnchors(ctx converter.Context, doc *nethtml.Node) {
+	title := p.currentTitle()
+	slugger := NewSlugger(p.headingIDs, title)
+
+	var walk func(n *nethtml.Node)
+	walk = func(n *nethtml.Node) {
+		// Children first, so anchors inside headings are gone before heading text is read
+		for child := n.FirstChild; child != nil; {
+			next := child.NextSibling
+			walk(child)
+			child = next
+		}
+		if n.Type != nethtml.ElementNode {
+			return
+		}
+
+		switch {
+		case n.Data == "ac:structured-macro" && attrValue(n, "ac:name") == "anchor":
+			name := macroParameter(n, "")
+			replaceWithAnchor(n, name, p.anchorID(title, name))
+		case n.Data == "span" && hasClass(n, "confluence-anchor-link"):
+			// View-format anchors already carry Confluence's ID
+			id := attrValue(n, "id")
+			if p.headingIDs == HeadingIDsGitHub {
+				id = githubSlug(strings.TrimPrefix(id, compact(title)+"-"))
+			}
+			replaceWithAnchor(n, id, id)
+		case p.headingIDs == HeadingIDsConfluence && isHeading(n):
+			if text := strings.TrimSpace(textContent(n)); text != "" {
+				n.InsertBefore(newAnchor(slugger.HeadingID(text)), n.FirstChild)
+			}
+		}
+	}
+	walk(doc)
+}
+
+// replaceWithAnchor swaps n for an anchor target, or removes it when the anchor has no name
+func replaceWithAnchor(n *nethtml.Node, name, id string) {
+	if n.Parent == nil {
+		return
+	}
+	if name != "" {
+		n.Parent.InsertBefore(newAnchor(id), n)
+	}
+	n.Parent.RemoveChild(n)
+}
+
+func newAnchor(id string) *nethtml.Node {
+	return &nethtml.Node{
+		Type: nethtml.ElementNode,
+		Data: anchorTag,
+		Attr: []nethtml.Attribute{{Key: "id", Val: id}},
+	}
+}
+
+func isHeading(n *nethtml.Node) bool {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return true
+	}
+	return false
+}
+
+// handleAnchor writes an anchor target as an inline HTML element
+func (p *ConfluencePlugin) handleAnchor(ctx converter.Context, w converter.Writer, n *nethtml.Node) converter.RenderStatus {
+	_, _ = fmt.Fprintf(w, `<a id="%s"></a>`, html.EscapeString(attrValue(n, "id")))
+	return converter.RenderSuccess
+}
@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestSlugger(t *testing.T) {
+	tests := []struct {
+		style HeadingIDStyle
+		texts []string
+		want  []string
+	}{
+		{
+			style: HeadingIDsConfluence,
+			texts: []string{"Rollback Plan", "Step 1: Prepare", "Rollback Plan"},
+			want:  []string{"ReleaseNotes-RollbackPlan", "ReleaseNotes-Step1:Prepare", "ReleaseNotes-RollbackPlan.1"},
+		},
+		{
+			style: HeadingIDsGitHub,
+			texts: []string{"Rollback Plan", "Step 1: Prepare (v2)", "Rollback Plan", "Über_alles"},
+			want:  []string{"rollback-plan", "step-1-prepare-v2", "rollback-plan-1", "über_alles"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			slugger := NewSlugger(tt.style, "Release Notes")
+			for i, text := range tt.texts {
+				if got := slugger.HeadingID(text); got != tt.want[i] {
+					t.Errorf("HeadingID(%q) = %q, want %q", text, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseHeadingIDStyle(t *testing.T) {
+	if style, err := ParseHeadingIDStyle(""); err != nil || style != HeadingIDsConfluence {
+		t.Fatalf("expected confluence default, got %q, %v", style, err)
+	}
+	if style, err := ParseHeadingIDStyle("GitHub"); err != nil || style != HeadingIDsGitHub {
+		t.Fatalf("expected github, got %q, %v", style, err)
+	}
+	if _, err := ParseHeadingIDStyle("kramdown"); err == nil {
+		t.Fatal("expected error for unknown style")
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	tests := []struct {
+		name  string
+		style HeadingIDStyle
+		html  string
+		want  string
+	}{
+		{
+			name:  "confluence heading ids",
+			style: HeadingIDsConfluence,
+			html:  `<h2>Rollback Plan</h2><p>text</p><h2>Rollback Plan</h2>`,
+			want:  "## <a id=\"ReleaseNotes-RollbackPlan\"></a>Rollback Plan\n\ntext\n\n## <a id=\"ReleaseNotes-RollbackPlan.1\"></a>Rollback Plan",
+		},
+		{
+			name:  "github headings keep renderer ids",
+			style: HeadingIDsGitHub,
+			html:  `<h2>Rollback Plan</h2>`,
+			want:  "## Rollback Plan",
+		},
+		{
+			name:  "anchor macro inside heading",
+			style: HeadingIDsConfluence,
+			html:  `<h2><ac:structured-macro ac:name="anchor"><ac:parameter ac:name="">rollback</ac:parameter></ac:structured-macro>Rollback</h2>`,
+			want:  "## <a id=\"ReleaseNotes-Rollback\"></a><a id=\"ReleaseNotes-rollback\"></a>Rollback",
+		},
+		{
+			name:  "github anchor macro in paragraph",
+			style: HeadingIDsGitHub,
+			html:  `<p>Before <ac:structured-macro ac:name="anchor"><ac:parameter ac:name="">Rollback Steps</ac:parameter></ac:structured-macro>after</p>`,
+			want:  "Before <a id=\"rollback-steps\"></a>after",
+		},
+		{
+			name:  "view anchor span",
+			style: HeadingIDsGitHub,
+			html:  `<p><span class="confluence-anchor-link" id="ReleaseNotes-Rollback Steps"></span>text</p>`,
+			want:  "<a id=\"rollback-steps\"></a>text",
+		},
+		{
+			name:  "same page anchor link",
+			style: HeadingIDsGitHub,
+			html:  `<p><ac:link ac:anchor="Rollback Plan"><ac:plain-text-link-body>roll back</ac:plain-text-link-body></ac:link></p>`,
+			want:  "[roll back](#rollback-plan)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewConfluencePlugin(nil, "assets")
+			plugin.SetHeadingIDStyle(tt.style)
+			plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Release Notes", SpaceKey: "DOCS"})
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package attachments
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// LocalService implements Resolver by reading attachments from a local directory,
+// for converting exports and saved API responses without Confluence access.
+type LocalService struct {
+	root string
+}
+
+// NewLocalService constructs an attachment service rooted at dir.
+func NewLocalService(dir string) *LocalService {
+	return &LocalService{root: dir}
+}
+
+// Resolve locates the attachment on disk and returns its content.
+func (s *LocalService) Resolve(page *model.ConfluencePage, filename string, revision int) (string, error) {
+	_, data, err := s.DownloadAttachment(page, filename, revision)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// DownloadAttachment reads attachment bytes for the given filename from disk.
+// The attachment's download link is tried relative to the root first, followed by
+// <root>/<pageID>/<filename> and <root>/<filename>.
+func (s *LocalService) DownloadAttachment(page *model.ConfluencePage, filename string, revision int) (*model.ConfluenceAttachment, []byte, error) {
+	if page == nil {
+		return nil, nil, fmt.Errorf("page context not provided")
+	}
+
+	attachment := selectAttachment(page.Attachments, filename, revision)
+
+	for _, candidate := range s.candidates(page, attachment, filename) {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(candidate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read attachment %s: %w", filename, err)
+		}
+
+		if attachment == nil {
+			attachment = &model.ConfluenceAttachment{
+				Title:        filename,
+				MediaType:    mime.TypeByExtension(filepath.Ext(filename)),
+				FileSize:     info.Size(),
+				DownloadLink: candidate,
+			}
+		}
+		return attachment, data, nil
+	}
+
+	return nil, nil, fmt.Errorf("attachment %s not found in %s", filename, s.root)
+}
+
+// candidates lists the paths an attachment may be stored at. Download links and
+// page IDs come from export files, so paths leading outside the root are dropped.
+func (s *LocalService) candidates(page *model.ConfluencePage, attachment *model.ConfluenceAttachment, filename string) []string {
+	var paths []string
+	add := func(elem ...string) {
+		path := filepath.Join(append([]string{s.root}, elem...)...)
+		if rel, err := filepath.Rel(s.root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			paths = append(paths, path)
+		}
+	}
+
+	if attachment != nil && attachment.DownloadLink != "" && !strings.Contains(attachment.DownloadLink, "://") {
+		link, _, _ := strings.Cut(attachment.DownloadLink, "?")
+		add(filepath.FromSlash(strings.TrimPrefix(link, "/")))
+	}
+
+	name := filepath.Base(filepath.FromSlash(filename))
+	if page.ID != "" {
+		add(page.ID, name)
+	}
+	add(name)
+
+	return paths
+}
@@ -0,0 +1,81 @@
+package attachments
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestLocalServiceDownloadAttachment(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "export")
+	writeFile(t, filepath.Join(root, "download", "attachments", "1", "diagram.png"), "png")
+	writeFile(t, filepath.Join(root, "1", "notes.txt"), "notes")
+	writeFile(t, filepath.Join(dir, "secret.txt"), "secret")
+
+	service := NewLocalService(root)
+
+	tests := []struct {
+		name     string
+		page     *model.ConfluencePage
+		filename string
+		want     string
+	}{
+		{
+			name: "download link",
+			page: &model.ConfluencePage{ID: "1", Attachments: []model.ConfluenceAttachment{
+				{Title: "diagram.png", DownloadLink: "/download/attachments/1/diagram.png?version=2"},
+			}},
+			filename: "diagram.png",
+			want:     "png",
+		},
+		{
+			name:     "page directory",
+			page:     &model.ConfluencePage{ID: "1"},
+			filename: "notes.txt",
+			want:     "notes",
+		},
+		{
+			name: "download link outside the root",
+			page: &model.ConfluencePage{ID: "1", Attachments: []model.ConfluenceAttachment{
+				{Title: "secret.txt", DownloadLink: "/../secret.txt"},
+			}},
+			filename: "secret.txt",
+		},
+		{
+			name:     "page ID outside the root",
+			page:     &model.ConfluencePage{ID: ".."},
+			filename: "secret.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, data, err := service.DownloadAttachment(tt.page, tt.filename, 0)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("expected an error, read %q", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadAttachment returned error: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("unexpected content: %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"golang.org/x/net/html"
+)
+
+// childListOptions controls which descendants a children or pagetree macro lists
+type childListOptions struct {
+	depth   int // Levels to list; 0 lists every descendant
+	sort    string
+	reverse bool
+}
+
+// handleChildrenMacro lists the current page's children, or those of the page
+// named by the macro's page parameter, as links
+func (p *ConfluencePlugin) handleChildrenMacro(n *html.Node) string {
+	if p.client == nil || p.currentPage == nil {
+		return "<!-- Child Pages -->"
+	}
+
+	parent := p.currentPage
+	if resource := parameterPage(n, "page"); resource != nil {
+		page, err := p.client.FindContent(p.contentRef(resource, model.ContentTypePage))
+		if err != nil || page == nil {
+			return fmt.Sprintf("<!-- Child Pages of %s not found -->", attrValue(resource, "ri:content-title"))
+		}
+		parent = page
+	}
+
+	opts := childListOptions{
+		depth:   1,
+		sort:    strings.ToLower(macroParameter(n, "sort")),
+		reverse: strings.EqualFold(macroParameter(n, "reverse"), "true"),
+	}
+	if depth, err := strconv.Atoi(macroParameter(n, "depth")); err == nil && depth >= 0 {
+		opts.depth = depth
+	}
+	if strings.EqualFold(macroParameter(n, "all"), "true") {
+		opts.depth = 0
+	}
+	return p.childPageList(parent.ID, opts)
+}
+
+// handlePageTreeMacro lists every descendant of the macro's root page, which
+// defaults to the space home page
+func (p *ConfluencePlugin) handlePageTreeMacro(n *html.Node) string {
+	if p.client == nil || p.currentPage == nil {
+		return "<!-- Page Tree -->"
+	}
+
+	root := strings.TrimSpace(macroParameter(n, "root"))
+	resource := parameterPage(n, "root")
+	if resource != nil {
+		root = attrValue(resource, "ri:content-title")
+	}
+
+	var page *model.ConfluencePage
+	var err error
+	switch root {
+	case "", "@home":
+		spaceKey := macroParameter(n, "spaceKey")
+		if spaceKey == "" {
+			spaceKey = p.currentPage.SpaceKey
+		}
+		page, err = p.client.FindContent(model.ContentRef{Type: model.ContentTypeSpace, SpaceKey: spaceKey})
+	case "@self":
+		page = p.currentPage
+	case "@parent", "@none":
+		return fmt.Sprintf("<!-- Page Tree rooted at %s -->", root)
+	default:
+		ref := model.ContentRef{Type: model.ContentTypePage, SpaceKey: p.currentPage.SpaceKey, Title: root}
+		if resource != nil {
+			ref = p.contentRef(resource, model.ContentTypePage)
+		}
+		page, err = p.client.FindContent(ref)
+	}
+	if err != nil || page == nil {
+		return fmt.Sprintf("<!-- Page Tree root %s not found -->", root)
+	}
+
+	return p.childPageList(page.ID, childListOptions{
+		sort:    strings.ToLower(macroParameter(n, "sort")),
+		reverse: strings.EqualFold(macroParameter(n, "reverse"), "true"),
+	})
+}
+
+// parameterPage returns the ri:page referenced by a macro parameter
+func parameterPage(n *html.Node, name string) *html.Node {
+	return parameterElement(n, name, "ri:page")
+}
+
+// childPageList renders the descendants of a page as a nested list of
+// confluence:// links, which the link rewriting pass points at converted files.
+// Child pages come from the client, whose cache already holds the children
+// fetched while walking a page tree.
+func (p *ConfluencePlugin) childPageList(pageID string, opts childListOptions) string {
+	var b strings.Builder
+	seen := map[string]bool{pageID: true}
+
+	var write func(id string, level int)
+	write = func(id string, level int) {
+		children, err := p.client.GetChildPages(id)
+		if err != nil {
+			log.Printf("Failed to get child pages of %s: %v", id, err)
+			return
+		}
+		for _, child := range sortPages(children, opts) {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			_, _ = fmt.Fprintf(&b, "%s- [%s](confluence://pageId/%s)\n", strings.Repeat("  ", level-1), linkTextEscaper.Replace(child.Title), child.ID)
+			if opts.depth == 0 || level < opts.depth {
+				write(child.ID, level+1)
+			}
+		}
+	}
+	write(pageID, 1)
+
+	if b.Len() == 0 {
+		return ""
+	}
+	// Blank lines keep the list apart from surrounding text, also inside list items
+	return "\n\n" + strings.TrimSuffix(b.String(), "\n") + "\n\n"
+}
+
+// sortPages orders pages by title, creation or modification date, keeping
+// Confluence's manual order by default
+func sortPages(pages []*model.ConfluencePage, opts childListOptions) []*model.ConfluencePage {
+	sorted := slices.Clone(pages)
+	switch opts.sort {
+	case "title", "natural", "bitwise":
+		slices.SortStableFunc(sorted, func(a, b *model.ConfluencePage) int {
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		})
+	case "creation":
+		slices.SortStableFunc(sorted, func(a, b *model.ConfluencePage) int {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		})
+	case "modified":
+		slices.SortStableFunc(sorted, func(a, b *model.ConfluencePage) int {
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		})
+	}
+	if opts.reverse {
+		slices.Reverse(sorted)
+	}
+	return sorted
+}
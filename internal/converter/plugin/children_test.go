@@ -0,0 +1,85 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestChildPageMacros(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	ctrl := gomock.NewController(t)
+	client := mock_confluence.NewMockClient(ctrl)
+	client.EXPECT().GetChildPages("1").Return([]*model.ConfluencePage{
+		{ID: "11", Title: "Setup", CreatedAt: day(3)},
+		{ID: "12", Title: "Architecture", CreatedAt: day(1)},
+	}, nil).AnyTimes()
+	client.EXPECT().GetChildPages("11").Return([]*model.ConfluencePage{{ID: "111", Title: "Linux [beta]"}}, nil).AnyTimes()
+	client.EXPECT().GetChildPages("7").Return([]*model.ConfluencePage{{ID: "1", Title: "Home"}}, nil).AnyTimes()
+	client.EXPECT().GetChildPages(gomock.Any()).Return(nil, nil).AnyTimes()
+	client.EXPECT().FindContent(model.ContentRef{Type: model.ContentTypeSpace, SpaceKey: "DOCS"}).
+		Return(&model.ConfluencePage{ID: "7", Title: "Docs", SpaceKey: "DOCS"}, nil).AnyTimes()
+	client.EXPECT().FindContent(model.ContentRef{Type: model.ContentTypePage, SpaceKey: "DOCS", Title: "Setup"}).
+		Return(&model.ConfluencePage{ID: "11", Title: "Setup", SpaceKey: "DOCS"}, nil).AnyTimes()
+
+	plugin := NewConfluencePluginWithClient(client, nil, "assets")
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS"})
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "direct children in page order",
+			html: `<ac:structured-macro ac:name="children" /><p>After</p>`,
+			want: "- [Setup](confluence://pageId/11)\n- [Architecture](confluence://pageId/12)\n\nAfter",
+		},
+		{
+			name: "all descendants sorted by title",
+			html: `<ac:structured-macro ac:name="children"><ac:parameter ac:name="all">true</ac:parameter><ac:parameter ac:name="sort">title</ac:parameter></ac:structured-macro>`,
+			want: "- [Architecture](confluence://pageId/12)\n- [Setup](confluence://pageId/11)\n  - [Linux \\[beta\\]](confluence://pageId/111)",
+		},
+		{
+			name: "reverse creation order",
+			html: `<ac:structured-macro ac:name="children"><ac:parameter ac:name="sort">creation</ac:parameter><ac:parameter ac:name="reverse">true</ac:parameter></ac:structured-macro>`,
+			want: "- [Setup](confluence://pageId/11)\n- [Architecture](confluence://pageId/12)",
+		},
+		{
+			name: "children of another page",
+			html: `<ac:structured-macro ac:name="children"><ac:parameter ac:name="page"><ac:link><ri:page ri:content-title="Setup" /></ac:link></ac:parameter></ac:structured-macro>`,
+			want: "- [Linux \\[beta\\]](confluence://pageId/111)",
+		},
+		{
+			name: "page tree from space home",
+			html: `<ac:structured-macro ac:name="pagetree"><ac:parameter ac:name="root"><ac:link><ri:page ri:content-title="@home" /></ac:link></ac:parameter></ac:structured-macro>`,
+			want: "- [Home](confluence://pageId/1)\n  - [Setup](confluence://pageId/11)\n    - [Linux \\[beta\\]](confluence://pageId/111)\n  - [Architecture](confluence://pageId/12)",
+		},
+		{
+			name: "page tree of current page",
+			html: `<ac:structured-macro ac:name="pagetree"><ac:parameter ac:name="root"><ac:link><ri:page ri:content-title="@self" /></ac:link></ac:parameter><ac:parameter ac:name="sort">title</ac:parameter></ac:structured-macro>`,
+			want: "- [Architecture](confluence://pageId/12)\n- [Setup](confluence://pageId/11)\n  - [Linux \\[beta\\]](confluence://pageId/111)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package plugin
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// CodeTitleStyle selects how the titles of code blocks are written
+type CodeTitleStyle string
+
+const (
+	// CodeTitlesCaption writes the title in bold above the block, which every renderer shows
+	CodeTitlesCaption CodeTitleStyle = "caption"
+	// CodeTitlesInfo writes title="..." and linenums="..." in the fence info string,
+	// as MkDocs and Docusaurus expect
+	CodeTitlesInfo CodeTitleStyle = "info"
+)
+
+// SetCodeTitleStyle selects how code block titles are written
+func (p *ConfluencePlugin) SetCodeTitleStyle(style CodeTitleStyle) {
+	p.codeTitles = style
+}
+
+// ParseCodeTitleStyle validates a code title style name
+func ParseCodeTitleStyle(s string) (CodeTitleStyle, error) {
+	switch style := CodeTitleStyle(strings.ToLower(strings.TrimSpace(s))); style {
+	case CodeTitlesCaption, CodeTitlesInfo:
+		return style, nil
+	case "":
+		return CodeTitlesCaption, nil
+	default:
+		return "", fmt.Errorf("unsupported code title style: %s (use caption or info)", s)
+	}
+}
+
+// languageAliases maps Confluence code macro languages to the Linguist names
+// GitHub and most highlighters recognise
+var languageAliases = map[string]string{
+	"actionscript3": "actionscript",
+	"as3":           "actionscript",
+	"c#":            "csharp",
+	"cs":            "csharp",
+	"c++":           "cpp",
+	"coldfusion":    "cfm",
+	"cf":            "cfm",
+	"delphi":        "pascal",
+	"erl":           "erlang",
+	"html/xml":      "xml",
+	"xhtml":         "html",
+	"javafx":        "java",
+	"jfx":           "java",
+	"js":            "javascript",
+	"jscript":       "javascript",
+	"ts":            "typescript",
+	"shell":         "bash",
+	"sh":            "bash",
+	"ps":            "powershell",
+	"py":            "python",
+	"rb":            "ruby",
+	"vb":            "vbnet",
+	"yml":           "yaml",
+	"plain":         "text",
+	"none":          "",
+}
+
+// linguistLanguage returns the highlighter name for a Confluence code macro language
+func linguistLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	return language
+}
+
+// codeBlock is the content and presentation of a code or noformat macro
+type codeBlock struct {
+	language    string
+	title       string
+	lineNumbers bool
+	firstLine   int
+	collapse    bool
+	code        string
+}
+
+var backtickRun = regexp.MustCompile("`{3,}")
+
+// format writes the block as a fenced code block, titled in the given style and
+// wrapped in <details> when Confluence shows it collapsed
+func (b codeBlock) format(titles CodeTitleStyle) string {
+	// The fence must be longer than any backtick run in the code
+	fence := "```"
+	for _, run := range backtickRun.FindAllString(b.code, -1) {
+		if len(run) >= len(fence) {
+			fence = strings.Repeat("`", len(run)+1)
+		}
+	}
+
+	info := linguistLanguage(b.language)
+	caption := b.title
+	if titles == CodeTitlesInfo {
+		var attrs []string
+		if b.title != "" {
+			attrs = append(attrs, fmt.Sprintf("title=%q", b.title))
+		}
+		if b.lineNumbers {
+			attrs = append(attrs, fmt.Sprintf(`linenums="%d"`, max(b.firstLine, 1)))
+		}
+		if len(attrs) > 0 {
+			if info == "" {
+				info = "text"
+			}
+			info += " " + strings.Join(attrs, " ")
+		}
+		caption = ""
+	}
+	block := fence + info + "\n" + b.code + "\n" + fence + "\n"
+
+	if b.collapse {
+		summary := b.title
+		if summary == "" {
+			summary = "Code"
+		}
+		return fmt.Sprintf("<details>\n<summary>%s</summary>\n\n%s\n</details>\n", html.EscapeString(summary), block)
+	}
+	if caption != "" {
+		return "**" + caption + "**\n\n" + block
+	}
+	return block
+}
@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeBlockFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		block  codeBlock
+		titles CodeTitleStyle
+		want   string
+	}{
+		{
+			name:  "language alias",
+			block: codeBlock{language: "C#", code: "var x = 1;"},
+			want:  "```csharp\nvar x = 1;\n```\n",
+		},
+		{
+			name:  "no language",
+			block: codeBlock{language: "none", code: "plain"},
+			want:  "```\nplain\n```\n",
+		},
+		{
+			name:   "caption title",
+			block:  codeBlock{language: "js", title: "app.js", lineNumbers: true, code: "run()"},
+			titles: CodeTitlesCaption,
+			want:   "**app.js**\n\n```javascript\nrun()\n```\n",
+		},
+		{
+			name:   "info string title and line numbers",
+			block:  codeBlock{language: "shell", title: "setup.sh", lineNumbers: true, firstLine: 10, code: "make"},
+			titles: CodeTitlesInfo,
+			want:   "```bash title=\"setup.sh\" linenums=\"10\"\nmake\n```\n",
+		},
+		{
+			name:   "info string without language",
+			block:  codeBlock{title: "output", code: "ok"},
+			titles: CodeTitlesInfo,
+			want:   "```text title=\"output\"\nok\n```\n",
+		},
+		{
+			name:  "collapsed",
+			block: codeBlock{language: "actionscript3", title: "Main <v2>", collapse: true, code: "trace(1)"},
+			want:  "<details>\n<summary>Main &lt;v2&gt;</summary>\n\n```actionscript\ntrace(1)\n```\n\n</details>\n",
+		},
+		{
+			name:  "fence longer than code backticks",
+			block: codeBlock{language: "markdown", code: "```go\nx\n```"},
+			want:  "````markdown\n```go\nx\n```\n````\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.block.format(tt.titles); got != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoformatMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<ac:structured-macro ac:name="noformat"><ac:parameter ac:name="nopanel">true</ac:parameter><ac:plain-text-body><!--[CDATA[a  <b>
+  c]]></ac:plain-text-body></ac:structured-macro>`, "ac:structured-macro")
+	if got := plugin.handleCodeMacro(node); !strings.HasPrefix(got, "```\na  <b>\n  c\n```") {
+		t.Fatalf("unexpected noformat block: %q", got)
+	}
+}
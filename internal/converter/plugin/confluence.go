@@ -3,6 +3,7 @@ package plugin
 import (
 	"fmt"
 	"log"
+	"maps"
 	"net/url"
 	"strconv"
 	"strings"
@@ -11,7 +12,11 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/callout"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
+	mdmodel "github.com/jackchuka/confluence-md/internal/converter/model"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin/attachments"
+	"github.com/jackchuka/confluence-md/internal/jira"
 	"golang.org/x/net/html"
 )
 
@@ -20,6 +25,20 @@ type ConfluencePlugin struct {
 	attachmentResolver attachments.Resolver
 	client             confluence.Client
 	currentPage        *model.ConfluencePage
+	baseURL            string
+	headingIDs         HeadingIDStyle
+	jira               jira.Config
+	callouts           *callout.Renderer
+	tocPlaceholder     bool
+	preprocess         func(string) string
+	excerpt            string
+	tasks              []mdmodel.Task
+	assets             []Asset
+	textDiagrams       map[string]string // macro name -> code fence language
+	mathStyle          MathStyle
+	codeTitles         CodeTitleStyle
+	layoutStyle        layout.Style
+	mediaEmbeds        bool
 	userCache          map[string]string // accountID -> displayName
 }
 
@@ -28,6 +47,9 @@ func NewConfluencePlugin(resolver attachments.Resolver, imageFolder string) *Con
 	return &ConfluencePlugin{
 		imageFolder:        imageFolder,
 		attachmentResolver: resolver,
+		headingIDs:         HeadingIDsConfluence,
+		callouts:           callout.New(callout.StyleBlockquote, nil),
+		textDiagrams:       maps.Clone(DefaultTextDiagrams),
 		userCache:          make(map[string]string),
 	}
 }
@@ -38,6 +60,9 @@ func NewConfluencePluginWithClient(client confluence.Client, resolver attachment
 		imageFolder:        imageFolder,
 		attachmentResolver: resolver,
 		client:             client,
+		headingIDs:         HeadingIDsConfluence,
+		callouts:           callout.New(callout.StyleBlockquote, nil),
+		textDiagrams:       maps.Clone(DefaultTextDiagrams),
 		userCache:          make(map[string]string),
 	}
 }
@@ -45,6 +70,7 @@ func NewConfluencePluginWithClient(client confluence.Client, resolver attachment
 // SetCurrentPage records which page is currently being converted
 func (p *ConfluencePlugin) SetCurrentPage(page *model.ConfluencePage) {
 	p.currentPage = page
+	p.assets = nil
 
 	// Populate user cache from page metadata
 	if page != nil {
@@ -133,7 +159,19 @@ func (p *ConfluencePlugin) Init(conv *converter.Converter) error {
 	conv.Register.RendererFor("ac:image", converter.TagTypeInline, p.handleImage, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:emoticon", converter.TagTypeInline, p.handleEmoticon, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:structured-macro", converter.TagTypeBlock, p.handleMacro, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:adf-extension", converter.TagTypeBlock, p.handleADFExtension, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:adf-node", converter.TagTypeBlock, p.handleADFNode, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:link", converter.TagTypeInline, p.handleLink, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:task-list", converter.TagTypeBlock, p.handleTaskList, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:layout-section", converter.TagTypeBlock, p.handleLayoutSection, converter.PriorityStandard)
+	conv.Register.PreRenderer(p.spliceIncludes, converter.PriorityStandard)
+	conv.Register.PreRenderer(p.hoistMacroContent, converter.PriorityStandard)
+	conv.Register.PreRenderer(p.fillEmptyLinkBodies, converter.PriorityStandard)
+	conv.Register.PreRenderer(p.insertAnchors, converter.PriorityStandard)
+	conv.Register.PreRenderer(p.buildTOCs, converter.PriorityStandard)
+	conv.Register.PreRenderer(p.markInlineMacros, converter.PriorityStandard)
+	conv.Register.RendererFor(inlineMacroTag, converter.TagTypeInline, p.handleMacro, converter.PriorityStandard)
+	conv.Register.RendererFor(anchorTag, converter.TagTypeInline, p.handleAnchor, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:inline-comment-marker", converter.TagTypeInline, p.handleInlineComment, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:placeholder", converter.TagTypeInline, p.handlePlaceholder, converter.PriorityStandard)
 	conv.Register.RendererFor("time", converter.TagTypeInline, p.handleTime, converter.PriorityStandard)
@@ -214,61 +252,66 @@ func (p *ConfluencePlugin) getCellHTMLContent(ctx converter.Context, cell *html.
 // flattenCellContent recursively flattens cell content, converting headings to bold text
 func (p *ConfluencePlugin) flattenCellContent(ctx converter.Context, w *strings.Builder, n *html.Node) {
 	for child := n.FirstChild; child != nil; child = child.NextSibling {
-		switch child.Type {
-		case html.TextNode:
-			text := child.Data
-			if text != "" {
-				w.WriteString(text)
-			}
-		case html.ElementNode:
-			switch child.Data {
-			case "h1", "h2", "h3", "h4", "h5", "h6":
-				// Convert headings to bold text
-				w.WriteString("<strong>")
+		p.flattenCellNode(ctx, w, child)
+	}
+}
+
+// flattenCellNode writes one node of a table cell on a single line
+func (p *ConfluencePlugin) flattenCellNode(ctx converter.Context, w *strings.Builder, child *html.Node) {
+	switch child.Type {
+	case html.TextNode:
+		text := child.Data
+		if text != "" {
+			w.WriteString(text)
+		}
+	case html.ElementNode:
+		switch child.Data {
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			// Convert headings to bold text
+			w.WriteString("<strong>")
+			p.flattenCellContent(ctx, w, child)
+			w.WriteString("</strong>")
+		case "br":
+			w.WriteString("<br>")
+		case "p":
+			// Skip empty <p/> tags
+			if child.FirstChild != nil {
 				p.flattenCellContent(ctx, w, child)
-				w.WriteString("</strong>")
-			case "br":
-				w.WriteString("<br>")
-			case "p":
-				// Skip empty <p/> tags
-				if child.FirstChild != nil {
-					p.flattenCellContent(ctx, w, child)
-					if child.NextSibling != nil {
-						w.WriteString(" ")
-					}
+				if child.NextSibling != nil {
+					w.WriteString(" ")
 				}
-			case "ul":
-				// Handle unordered lists
-				p.flattenListContent(ctx, w, child, false)
-			case "ol":
-				// Handle ordered lists
-				p.flattenListContent(ctx, w, child, true)
-			case "ac:task-list":
-				// Handle Confluence task lists
-				p.flattenTaskList(ctx, w, child)
-			case "strong", "b", "em", "i", "code", "a":
-				// Preserve these inline elements
-				var buf strings.Builder
-				_ = html.Render(&buf, child)
-				w.WriteString(buf.String())
-			case "ac:structured-macro":
-				p.handleMacro(ctx, w, child)
-			case "ac:emoticon":
-				p.handleEmoticon(ctx, w, child)
-				p.flattenCellContent(ctx, w, child)
-			case "ac:link":
-				p.handleLink(ctx, w, child)
-			case "time":
-				p.handleTime(ctx, w, child)
-				p.flattenCellContent(ctx, w, child)
-			case "ac:inline-comment-marker":
-				p.flattenCellContent(ctx, w, child)
-			case "ac:placeholder":
-				p.handlePlaceholder(ctx, w, child)
-			default:
-				// For other elements, recursively flatten
-				p.flattenCellContent(ctx, w, child)
 			}
+		case "ul":
+			// Handle unordered lists
+			p.flattenListContent(ctx, w, child, false)
+		case "ol":
+			// Handle ordered lists
+			p.flattenListContent(ctx, w, child, true)
+		case "ac:task-list":
+			// Handle Confluence task lists
+			p.flattenTaskList(ctx, w, child)
+		case "strong", "b", "em", "i", "code", "a":
+			// Preserve these inline elements
+			var buf strings.Builder
+			_ = html.Render(&buf, child)
+			w.WriteString(buf.String())
+		case "ac:structured-macro", inlineMacroTag:
+			p.handleMacro(ctx, w, child)
+		case "ac:emoticon":
+			p.handleEmoticon(ctx, w, child)
+			p.flattenCellContent(ctx, w, child)
+		case "ac:link":
+			p.handleLink(ctx, w, child)
+		case "time":
+			p.handleTime(ctx, w, child)
+			p.flattenCellContent(ctx, w, child)
+		case "ac:inline-comment-marker":
+			p.flattenCellContent(ctx, w, child)
+		case "ac:placeholder":
+			p.handlePlaceholder(ctx, w, child)
+		default:
+			// For other elements, recursively flatten
+			p.flattenCellContent(ctx, w, child)
 		}
 	}
 }
@@ -335,44 +378,6 @@ func (p *ConfluencePlugin) flattenListItemContent(ctx converter.Context, w *stri
 	}
 }
 
-// flattenTaskList handles Confluence task lists within table cells
-func (p *ConfluencePlugin) flattenTaskList(ctx converter.Context, w *strings.Builder, taskListNode *html.Node) {
-	w.WriteString("<br>")
-	for task := taskListNode.FirstChild; task != nil; task = task.NextSibling {
-		if task.Type != html.ElementNode || task.Data != "ac:task" {
-			continue
-		}
-
-		// Extract task status and body
-		status := "incomplete"
-		var body string
-
-		for child := task.FirstChild; child != nil; child = child.NextSibling {
-			if child.Type != html.ElementNode {
-				continue
-			}
-
-			if child.Data == "ac:task-status" && child.FirstChild != nil {
-				status = child.FirstChild.Data
-			} else if child.Data == "ac:task-body" {
-				var buf strings.Builder
-				p.flattenCellContent(ctx, &buf, child)
-				body = buf.String()
-			}
-		}
-
-		// Write checkbox based on status
-		if status == "complete" {
-			w.WriteString("☑ ")
-		} else {
-			w.WriteString("☐ ")
-		}
-
-		w.WriteString(body)
-		w.WriteString("<br>")
-	}
-}
-
 // handleTable converts HTML tables to markdown tables, preserving HTML content for complex cells
 func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
 	// Extract table data
@@ -557,6 +562,65 @@ func (p *ConfluencePlugin) handleEmoticon(ctx converter.Context, w converter.Wri
 	return converter.RenderTryNext
 }
 
+// bodylessMacros never have a body. Written self-closing, as Confluence stores them,
+// they are parsed as open elements that swallow the rest of the page.
+var bodylessMacros = map[string]bool{
+	"toc":      true,
+	"children": true,
+	"pagetree": true,
+}
+
+// hoistMacroContent moves everything but the parameters out of bodyless macros,
+// and everything out of dates, which are self-closing too
+func (p *ConfluencePlugin) hoistMacroContent(ctx converter.Context, doc *html.Node) {
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if n.Type != html.ElementNode {
+			return
+		}
+		if n.Data != "time" && (n.Data != "ac:structured-macro" || !bodylessMacros[attrValue(n, "ac:name")]) {
+			return
+		}
+		for child := n.LastChild; child != nil; {
+			prev := child.PrevSibling
+			if child.Type != html.ElementNode || child.Data != "ac:parameter" {
+				n.RemoveChild(child)
+				n.Parent.InsertBefore(child, n.NextSibling)
+			}
+			child = prev
+		}
+	}
+	walk(doc)
+}
+
+// inlineMacros lists macros that always render to a single line of text
+var inlineMacros = map[string]bool{
+	"status":     true,
+	"jira":       true,
+	"mathinline": true,
+}
+
+// inlineMacroTag is the element inline macros are renamed to, so whitespace
+// around them is kept as it is for other inline elements
+const inlineMacroTag = "confluence-inline-macro"
+
+// markInlineMacros renames inline macros before whitespace is collapsed
+func (p *ConfluencePlugin) markInlineMacros(ctx converter.Context, doc *html.Node) {
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "ac:structured-macro" && inlineMacros[attrValue(n, "ac:name")] {
+			n.Data = inlineMacroTag
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+}
+
 func (p *ConfluencePlugin) handleMacro(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
 	macroName := ""
 	for _, attr := range n.Attr {
@@ -576,17 +640,15 @@ func (p *ConfluencePlugin) handleMacro(ctx converter.Context, w converter.Writer
 	var result string
 	switch macroName {
 	case "info":
-		result = p.handleBlockquoteMacro(ctx, n, "ℹ️", "Info")
+		result = p.handleCalloutMacro(ctx, n, callout.KindInfo)
 	case "warning":
-		result = p.handleBlockquoteMacro(ctx, n, "⚠️", "Warning")
+		result = p.handleCalloutMacro(ctx, n, callout.KindWarning)
 	case "note":
-		result = p.handleBlockquoteMacro(ctx, n, "📝", "Note")
+		result = p.handleCalloutMacro(ctx, n, callout.KindNote)
 	case "tip":
-		result = p.handleBlockquoteMacro(ctx, n, "💡", "Tip")
-	case "code":
+		result = p.handleCalloutMacro(ctx, n, callout.KindTip)
+	case "code", "noformat":
 		result = p.handleCodeMacro(n)
-	case "mermaid-cloud":
-		result = p.handleMermaidMacro(n)
 	case "expand":
 		result = p.handleExpandMacro(ctx, n)
 	case "toc":
@@ -596,8 +658,49 @@ func (p *ConfluencePlugin) handleMacro(ctx converter.Context, w converter.Writer
 	case "status":
 		result = p.handleStatusMacro(n)
 	case "children":
-		result = "<!-- Child Pages -->"
+		result = p.handleChildrenMacro(n)
+	case "pagetree":
+		result = p.handlePageTreeMacro(n)
+	case "include", "excerpt-include":
+		result = p.handleIncludeMacro(n)
+	case "excerpt":
+		result = p.handleExcerptMacro(ctx, n)
+	case "jira":
+		result = p.handleJiraMacro(n)
+	case "panel":
+		result = p.handlePanelMacro(ctx, n)
+	case "section":
+		result = p.handleSectionMacro(ctx, n)
+	case "column":
+		// A column outside a section is a single column
+		result = "\n\n" + p.convertNestedHTML(ctx, n) + "\n\n"
+	case "multimedia":
+		result = p.handleMultimediaMacro(n)
+	case "widget":
+		result = p.handleWidgetMacro(n)
+	case "attachments":
+		result = p.handleAttachmentsMacro(n)
 	default:
+		if diagram, ok := diagramMacros[macroName]; ok {
+			result = p.handleDiagramMacro(n, diagram)
+			break
+		}
+		if inline, ok := mathMacros[macroName]; ok {
+			result = p.handleMathMacro(n, inline)
+			break
+		}
+		if fileViewerMacros[macroName] {
+			result = p.handleFileViewerMacro(n)
+			break
+		}
+		if listing, ok := listingMacros[macroName]; ok {
+			result = p.handleListingMacro(n, listing)
+			break
+		}
+		if language, ok := p.textDiagrams[macroName]; ok {
+			result = p.handleTextDiagramMacro(n, language)
+			break
+		}
 		result = fmt.Sprintf("<!-- Unsupported macro: %s -->", macroName)
 	}
 
@@ -608,31 +711,16 @@ func (p *ConfluencePlugin) handleMacro(ctx converter.Context, w converter.Writer
 	return converter.RenderSuccess
 }
 
-func (p *ConfluencePlugin) handleBlockquoteMacro(ctx converter.Context, n *html.Node, emoji, label string) string {
-	content := p.convertNestedHTML(ctx, n)
-	prefix := fmt.Sprintf("%s **%s:**", emoji, label)
-
-	if content == "" {
-		return "> " + prefix
-	}
-
-	// Handle multi-line content for blockquotes
-	lines := strings.Split(content, "\n")
-	if len(lines) > 1 {
-		result := "> " + prefix + "\n"
-		for _, line := range lines {
-			if strings.TrimSpace(line) != "" {
-				result += "> " + line + "\n"
-			} else {
-				result += ">\n"
-			}
-		}
-		return strings.TrimRight(result, "\n")
-	}
-	return fmt.Sprintf("> %s %s", prefix, content)
+// handleCalloutMacro converts info, warning, note and tip macros in the configured callout style
+func (p *ConfluencePlugin) handleCalloutMacro(ctx converter.Context, n *html.Node, kind callout.Kind) string {
+	return p.callouts.Render(callout.Callout{
+		Kind:    kind,
+		Title:   macroParameter(n, "title"),
+		Content: p.convertNestedHTML(ctx, n),
+	})
 }
 
-// handleCodeMacro converts code macros to code blocks
+// handleCodeMacro converts code and noformat macros to fenced code blocks
 func (p *ConfluencePlugin) handleCodeMacro(n *html.Node) string {
 	// Convert node to goquery selection for compatibility with existing logic
 	var buf strings.Builder
@@ -643,55 +731,21 @@ func (p *ConfluencePlugin) handleCodeMacro(n *html.Node) string {
 	}
 	selection := doc.Selection
 	rawHTML, _ := selection.Html()
-	language := extractLanguageParameter(rawHTML)
 
 	code := extractPlainTextBodyContent(selection, rawHTML)
 	if code == "" {
 		code = extractCodeContent(rawHTML)
 	}
 
-	if language != "" {
-		return fmt.Sprintf("```%s\n%s\n```\n", language, code)
-	}
-	return fmt.Sprintf("```\n%s\n```\n", code)
-}
-
-func (p *ConfluencePlugin) handleMermaidMacro(n *html.Node) string {
-	var buf strings.Builder
-	_ = html.Render(&buf, n)
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
-	if err != nil {
-		return fmt.Sprintf("<!-- Error rendering macro: %s -->", err.Error())
-	}
-	selection := doc.Selection
-
-	filename := extractMacroParameter(selection, "filename")
-	revisionStr := extractMacroParameter(selection, "revision")
-	revision := 0
-	if revisionStr != "" {
-		if parsed, err := strconv.Atoi(strings.TrimSpace(revisionStr)); err == nil {
-			revision = parsed
-		}
-	}
-
-	if filename == "" {
-		return "<!-- Mermaid macro missing filename -->"
-	}
-	if p.attachmentResolver == nil {
-		return fmt.Sprintf("<!-- Mermaid attachment %s unavailable -->", filename)
-	}
-	if p.currentPage == nil {
-		return fmt.Sprintf("<!-- Mermaid attachment %s unavailable -->", filename)
-	}
-	diagram, err := p.attachmentResolver.Resolve(p.currentPage, filename, revision)
-	if err != nil {
-		return fmt.Sprintf("<!-- Failed to load mermaid %s: %v -->", filename, err)
-	}
-	diagram = strings.TrimSpace(diagram)
-	if diagram == "" {
-		return "<!-- Empty mermaid macro -->"
-	}
-	return fmt.Sprintf("```mermaid\n%s\n```\n", diagram)
+	firstLine, _ := strconv.Atoi(macroParameter(n, "firstline"))
+	return codeBlock{
+		language:    extractLanguageParameter(rawHTML),
+		title:       macroParameter(n, "title"),
+		lineNumbers: strings.EqualFold(macroParameter(n, "linenumbers"), "true"),
+		firstLine:   firstLine,
+		collapse:    strings.EqualFold(macroParameter(n, "collapse"), "true"),
+		code:        code,
+	}.format(p.codeTitles)
 }
 
 func (p *ConfluencePlugin) handleTocMacro(n *html.Node) (string, bool) {
@@ -718,19 +772,36 @@ func (p *ConfluencePlugin) handleTocMacro(n *html.Node) (string, bool) {
 func (p *ConfluencePlugin) handleExpandMacro(ctx converter.Context, n *html.Node) string {
 	// Extract content from rich-text-body using recursive conversion
 	content := p.convertNestedHTML(ctx, n)
+	if content == "" {
+		return ""
+	}
 
-	// Just return the content directly without wrapper - content is already rendered
-	if content != "" {
-		return content + "\n\n"
+	// The default callout style returns the content without a wrapper
+	rendered := p.callouts.Render(callout.Callout{
+		Kind:    callout.KindExpand,
+		Title:   macroParameter(n, "title"),
+		Content: content,
+	}) + "\n\n"
+	if p.callouts.ExpandDetails() {
+		// Keeps <details> blocks inside list items off the item's first line
+		return "\n\n" + rendered
 	}
+	return rendered
+}
 
-	return ""
+// SetCallouts selects the renderer for callout macros, panels and expands
+func (p *ConfluencePlugin) SetCallouts(r *callout.Renderer) {
+	p.callouts = r
 }
 
 // convertNestedHTML recursively converts HTML content within macro nodes
 func (p *ConfluencePlugin) convertNestedHTML(ctx converter.Context, n *html.Node) string {
 	// Find ac:rich-text-body node
-	richTextBody := p.findRichTextBodyNode(n)
+	return p.convertBody(ctx, p.findRichTextBodyNode(n))
+}
+
+// convertBody converts the direct children of a macro body element
+func (p *ConfluencePlugin) convertBody(ctx converter.Context, richTextBody *html.Node) string {
 	if richTextBody == nil {
 		return ""
 	}
@@ -847,6 +918,11 @@ func (p *ConfluencePlugin) handleStatusMacro(n *html.Node) string {
 		}
 	}
 
+	return formatStatus(title, colour)
+}
+
+// formatStatus renders a status lozenge as an emoji badge
+func formatStatus(title, colour string) string {
 	// Map colours to emojis for better visibility
 	emoji := ""
 	switch strings.ToLower(colour) {
@@ -872,7 +948,15 @@ func (p *ConfluencePlugin) handleStatusMacro(n *html.Node) string {
 	return ""
 }
 
-// handleLink converts Confluence user links and other ac:link elements
+// userName returns a user's display name, or their account ID when it is unknown
+func (p *ConfluencePlugin) userName(accountID string) string {
+	if displayName, ok := p.userCache[accountID]; ok {
+		return displayName
+	}
+	return "user(" + accountID + ")"
+}
+
+// handleLink converts Confluence user, page, blog post, space and anchor links
 func (p *ConfluencePlugin) handleLink(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
 	// Look for ri:user child node
 	for child := n.FirstChild; child != nil; child = child.NextSibling {
@@ -886,18 +970,17 @@ func (p *ConfluencePlugin) handleLink(ctx converter.Context, w converter.Writer,
 			}
 
 			if accountID != "" {
-				if displayName, ok := p.userCache[accountID]; ok {
-					_, _ = fmt.Fprintf(w, " @%s ", displayName)
-				} else {
-					// Fallback to account ID
-					_, _ = fmt.Fprintf(w, " @user(%s) ", accountID)
-				}
+				_, _ = fmt.Fprintf(w, " @%s ", p.userName(accountID))
 				return converter.RenderTryNext
 			}
 		}
 	}
 
-	// If not a user link, let default handler try
+	if p.handleContentLink(ctx, w, n) {
+		return converter.RenderSuccess
+	}
+
+	// Otherwise let the default handler try
 	return converter.RenderTryNext
 }
 
@@ -135,7 +135,7 @@ func TestHandleMermaidCloudMacro(t *testing.T) {
 	plugin := &ConfluencePlugin{attachmentResolver: mockResolver}
 	plugin.SetCurrentPage(page)
 	node := findNode(t, `<ac:structured-macro ac:name="mermaid-cloud"><ac:parameter ac:name="filename">diagram</ac:parameter><ac:parameter ac:name="revision">2</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
-	result := plugin.handleMermaidMacro(node)
+	result := plugin.handleTextDiagramMacro(node, "mermaid")
 	expected := "```mermaid\ngraph TD;\nA-->B;\n```\n"
 	if result != expected {
 		t.Fatalf("unexpected mermaid cloud block: %q", result)
@@ -146,7 +146,7 @@ func TestHandleMermaidCloudMacroMissingResolver(t *testing.T) {
 	plugin := &ConfluencePlugin{}
 	plugin.SetCurrentPage(&model.ConfluencePage{ID: "123"})
 	node := findNode(t, `<ac:structured-macro ac:name="mermaid-cloud"><ac:parameter ac:name="filename">diagram</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
-	result := plugin.handleMermaidMacro(node)
+	result := plugin.handleTextDiagramMacro(node, "mermaid")
 	if !strings.Contains(result, "Mermaid attachment diagram unavailable") {
 		t.Fatalf("expected unavailable message, got %q", result)
 	}
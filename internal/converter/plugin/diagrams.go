@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Asset is a file fetched while converting a page, to be written to the image folder
+type Asset struct {
+	FileName string
+	Data     []byte
+}
+
+// diagramMacro describes an editor macro that stores its diagram as page attachments
+type diagramMacro struct {
+	label     string // Name used in placeholder comments
+	extension string // Extension the editable source is saved with
+}
+
+var diagramMacros = map[string]diagramMacro{
+	"drawio":        {label: "draw.io", extension: ".drawio"},
+	"drawio-sketch": {label: "draw.io", extension: ".drawio"},
+	"gliffy":        {label: "Gliffy", extension: ".gliffy"},
+}
+
+// Assets returns the files fetched for the current page, such as diagram sources and previews
+func (p *ConfluencePlugin) Assets() []Asset {
+	return p.assets
+}
+
+// handleDiagramMacro saves a diagram's editable source and PNG preview as assets,
+// and embeds the preview linked to the source. When attachments are not saved,
+// the preview and source are linked on the site instead.
+func (p *ConfluencePlugin) handleDiagramMacro(n *html.Node, macro diagramMacro) string {
+	name := macroParameter(n, "diagramName")
+	if name == "" {
+		name = macroParameter(n, "name")
+	}
+	if name == "" {
+		return fmt.Sprintf("<!-- %s macro missing diagram name -->", macro.label)
+	}
+	if p.currentPage == nil {
+		return fmt.Sprintf("<!-- %s diagram %s unavailable -->", macro.label, name)
+	}
+
+	// The source is attached under the diagram name, with or without an extension
+	sourceNames := []string{name, name + macro.extension}
+	var source, preview string
+	if p.imageFolder == "" {
+		source = p.siteAttachmentLink(sourceNames...)
+		preview = p.siteAttachmentLink(name + ".png")
+	} else {
+		if p.attachmentResolver == nil {
+			return fmt.Sprintf("<!-- %s diagram %s unavailable -->", macro.label, name)
+		}
+
+		revision := 0
+		for _, param := range []string{"revision", "version"} {
+			if parsed, err := strconv.Atoi(macroParameter(n, param)); err == nil {
+				revision = parsed
+				break
+			}
+		}
+
+		if saved := p.fetchAsset(strings.TrimSuffix(name, macro.extension)+macro.extension, revision, sourceNames...); saved != "" {
+			source = p.assetPath(saved)
+		}
+		if saved := p.fetchAsset(name+".png", 0, name+".png"); saved != "" {
+			preview = p.assetPath(saved)
+		}
+	}
+
+	alt := linkTextEscaper.Replace(name)
+	switch {
+	case source != "" && preview != "":
+		return fmt.Sprintf("[![%s](%s)](%s)", alt, preview, source)
+	case preview != "":
+		return fmt.Sprintf("![%s](%s)", alt, preview)
+	case source != "":
+		return fmt.Sprintf("[%s](%s)", alt, source)
+	default:
+		return fmt.Sprintf("<!-- %s diagram %s unavailable -->", macro.label, name)
+	}
+}
+
+// fetchAsset downloads the first attachment found among candidates and records it
+// under fileName, returning fileName or an empty string when none exists
+func (p *ConfluencePlugin) fetchAsset(fileName string, revision int, candidates ...string) string {
+	fileName = path.Base(fileName)
+	for _, asset := range p.assets {
+		if asset.FileName == fileName {
+			return fileName
+		}
+	}
+	for _, candidate := range candidates {
+		_, data, err := p.attachmentResolver.DownloadAttachment(p.currentPage, candidate, revision)
+		if err != nil {
+			continue
+		}
+		p.assets = append(p.assets, Asset{FileName: fileName, Data: data})
+		return fileName
+	}
+	return ""
+}
+
+// siteAttachmentLink links to the first of names the current page has an attachment
+// for. Without an attachment list, the first name is assumed to exist.
+func (p *ConfluencePlugin) siteAttachmentLink(names ...string) string {
+	if len(p.currentPage.Attachments) == 0 {
+		return p.remoteAttachmentLink(p.currentPage.ID, names[0])
+	}
+	for _, name := range names {
+		for _, attachment := range p.currentPage.Attachments {
+			if attachment.Title == name {
+				return p.remoteAttachmentLink(p.currentPage.ID, name)
+			}
+		}
+	}
+	return ""
+}
+
+func (p *ConfluencePlugin) assetPath(fileName string) string {
+	return url.PathEscape(p.imageFolder + "/" + fileName)
+}
@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	mock_attachments "github.com/jackchuka/confluence-md/internal/converter/plugin/attachments/mock"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestDiagramMacros(t *testing.T) {
+	files := map[string]string{
+		"Architecture":       "<mxfile/>",
+		"Architecture.png":   "png",
+		"Flow.gliffy":        "{}",
+		"Sketch preview.png": "png",
+	}
+
+	tests := []struct {
+		name       string
+		html       string
+		want       string
+		wantAssets []string
+	}{
+		{
+			name:       "drawio source and preview",
+			html:       `<ac:structured-macro ac:name="drawio"><ac:parameter ac:name="diagramName">Architecture</ac:parameter><ac:parameter ac:name="revision">3</ac:parameter></ac:structured-macro>`,
+			want:       "[![Architecture](assets%2FArchitecture.png)](assets%2FArchitecture.drawio)",
+			wantAssets: []string{"Architecture.drawio", "Architecture.png"},
+		},
+		{
+			name:       "gliffy source without preview",
+			html:       `<ac:structured-macro ac:name="gliffy"><ac:parameter ac:name="name">Flow</ac:parameter></ac:structured-macro>`,
+			want:       "[Flow](assets%2FFlow.gliffy)",
+			wantAssets: []string{"Flow.gliffy"},
+		},
+		{
+			name:       "preview only",
+			html:       `<ac:structured-macro ac:name="drawio"><ac:parameter ac:name="diagramName">Sketch preview</ac:parameter></ac:structured-macro>`,
+			want:       "![Sketch preview](assets%2FSketch%20preview.png)",
+			wantAssets: []string{"Sketch preview.png"},
+		},
+		{
+			name: "missing attachments",
+			html: `<ac:structured-macro ac:name="drawio"><ac:parameter ac:name="diagramName">Gone</ac:parameter></ac:structured-macro>`,
+			want: "<!-- draw.io diagram Gone unavailable -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			resolver := mock_attachments.NewMockResolver(ctrl)
+			resolver.EXPECT().DownloadAttachment(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(page *model.ConfluencePage, filename string, revision int) (*model.ConfluenceAttachment, []byte, error) {
+					data, ok := files[filename]
+					if !ok {
+						return nil, nil, fmt.Errorf("attachment %s not found", filename)
+					}
+					return &model.ConfluenceAttachment{Title: filename}, []byte(data), nil
+				}).AnyTimes()
+
+			plugin := NewConfluencePlugin(resolver, "assets")
+			plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS"})
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+
+			var assets []string
+			for _, asset := range plugin.Assets() {
+				assets = append(assets, asset.FileName)
+			}
+			if strings.Join(assets, ",") != strings.Join(tt.wantAssets, ",") {
+				t.Fatalf("unexpected assets: %v, want %v", assets, tt.wantAssets)
+			}
+		})
+	}
+}
+
+func TestDiagramMacrosWithoutDownloads(t *testing.T) {
+	// No EXPECT: attachments must not be fetched when they are not saved
+	ctrl := gomock.NewController(t)
+	plugin := NewConfluencePlugin(mock_attachments.NewMockResolver(ctrl), "")
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS", Attachments: []model.ConfluenceAttachment{
+		{Title: "Architecture"}, {Title: "Architecture.png"}, {Title: "Flow.gliffy"},
+	}})
+	plugin.SetBaseURL("https://example.atlassian.net/")
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "drawio source and preview",
+			html: `<ac:structured-macro ac:name="drawio"><ac:parameter ac:name="diagramName">Architecture</ac:parameter></ac:structured-macro>`,
+			want: "[![Architecture](https://example.atlassian.net/wiki/download/attachments/1/Architecture.png)](https://example.atlassian.net/wiki/download/attachments/1/Architecture)",
+		},
+		{
+			name: "gliffy source without preview",
+			html: `<ac:structured-macro ac:name="gliffy"><ac:parameter ac:name="name">Flow</ac:parameter></ac:structured-macro>`,
+			want: "[Flow](https://example.atlassian.net/wiki/download/attachments/1/Flow.gliffy)",
+		},
+		{
+			name: "missing attachments",
+			html: `<ac:structured-macro ac:name="drawio"><ac:parameter ac:name="diagramName">Gone</ac:parameter></ac:structured-macro>`,
+			want: "<!-- draw.io diagram Gone unavailable -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+	if assets := plugin.Assets(); len(assets) != 0 {
+		t.Fatalf("unexpected assets: %v", assets)
+	}
+}
@@ -0,0 +1,191 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxIncludeDepth limits how many levels of included pages are spliced in
+const maxIncludeDepth = 5
+
+// includeErrorAttr records on an include macro why its page was not spliced in
+const includeErrorAttr = "data-include-error"
+
+// SetBodyPreprocessor sets how included page bodies are prepared before parsing,
+// matching the preparation of the page being converted
+func (p *ConfluencePlugin) SetBodyPreprocessor(fn func(body string) string) {
+	p.preprocess = fn
+}
+
+// Excerpt returns the plain text of the current page's excerpt macro
+func (p *ConfluencePlugin) Excerpt() string {
+	return p.excerpt
+}
+
+// spliceIncludes records the page's excerpt and tasks, then replaces include and
+// excerpt-include macros with the body of the page they reference
+func (p *ConfluencePlugin) spliceIncludes(ctx converter.Context, doc *html.Node) {
+	p.excerpt = ""
+	if excerpt := findMacro(doc, "excerpt"); excerpt != nil {
+		p.excerpt = strings.Join(strings.Fields(textContent(p.findRichTextBodyNode(excerpt))), " ")
+	}
+	// Tasks are collected before splicing, so included tasks stay with the page that has them
+	p.collectTasks(doc)
+
+	if p.client == nil {
+		return
+	}
+	var stack []string
+	if p.currentPage != nil {
+		stack = append(stack, p.currentPage.ID)
+	}
+	p.expandIncludes(doc, stack)
+}
+
+// expandIncludes splices in the pages included beneath root. stack holds the IDs
+// of the pages being included, outermost first, to stop include cycles.
+func (p *ConfluencePlugin) expandIncludes(root *html.Node, stack []string) {
+	var macros []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "ac:structured-macro" {
+			switch attrValue(n, "ac:name") {
+			case "include", "excerpt-include":
+				macros = append(macros, n)
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	for _, macro := range macros {
+		nodes, err := p.includedNodes(macro, stack)
+		if err != nil {
+			macro.Attr = append(macro.Attr, html.Attribute{Key: includeErrorAttr, Val: err.Error()})
+			continue
+		}
+		for _, node := range nodes {
+			macro.Parent.InsertBefore(node, macro)
+		}
+		macro.Parent.RemoveChild(macro)
+	}
+}
+
+// includedNodes parses the body, or only the excerpt, of the page an include macro references
+func (p *ConfluencePlugin) includedNodes(macro *html.Node, stack []string) ([]*html.Node, error) {
+	resource := parameterPage(macro, "")
+	if resource == nil {
+		return nil, fmt.Errorf("no page referenced")
+	}
+	title := attrValue(resource, "ri:content-title")
+
+	found, err := p.client.FindContent(p.contentRef(resource, model.ContentTypePage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", title, err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%s not found", title)
+	}
+	if slices.Contains(stack, found.ID) {
+		return nil, fmt.Errorf("%s includes itself", title)
+	}
+	if len(stack) > maxIncludeDepth {
+		return nil, fmt.Errorf("%s is nested more than %d includes deep", title, maxIncludeDepth)
+	}
+
+	page, err := p.client.GetPage(found.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", title, err)
+	}
+	if page.Content.Storage.Representation == model.RepresentationADF {
+		return nil, fmt.Errorf("%s has no storage format body", title)
+	}
+
+	body := page.Content.Storage.Value
+	if p.preprocess != nil {
+		body = p.preprocess(body)
+	}
+	container := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(body), container)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", title, err)
+	}
+	for _, node := range nodes {
+		container.AppendChild(node)
+	}
+
+	if attrValue(macro, "ac:name") == "excerpt-include" {
+		excerpt := findMacro(container, "excerpt")
+		if excerpt == nil {
+			return nil, fmt.Errorf("%s has no excerpt", title)
+		}
+		container = p.findRichTextBodyNode(excerpt)
+		if container == nil {
+			return nil, nil
+		}
+	}
+
+	// Links without a space refer to the included page's space
+	qualifyContentRefs(container, page.SpaceKey)
+	p.expandIncludes(container, append(slices.Clone(stack), page.ID))
+
+	var spliced []*html.Node
+	for child := container.FirstChild; child != nil; {
+		next := child.NextSibling
+		container.RemoveChild(child)
+		spliced = append(spliced, child)
+		child = next
+	}
+	return spliced, nil
+}
+
+// findMacro returns the first macro with the given name beneath n
+func findMacro(n *html.Node, name string) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:structured-macro" && attrValue(child, "ac:name") == name {
+			return child
+		}
+		if found := findMacro(child, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// qualifyContentRefs adds the space key to page and blog post references that omit it
+func qualifyContentRefs(n *html.Node, spaceKey string) {
+	if spaceKey == "" {
+		return
+	}
+	if n.Type == html.ElementNode && (n.Data == "ri:page" || n.Data == "ri:blog-post") && attrValue(n, "ri:space-key") == "" {
+		n.Attr = append(n.Attr, html.Attribute{Key: "ri:space-key", Val: spaceKey})
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		qualifyContentRefs(child, spaceKey)
+	}
+}
+
+// handleExcerptMacro shows the excerpt in place unless it is hidden
+func (p *ConfluencePlugin) handleExcerptMacro(ctx converter.Context, n *html.Node) string {
+	if strings.EqualFold(macroParameter(n, "hidden"), "true") {
+		return ""
+	}
+	return p.convertNestedHTML(ctx, n)
+}
+
+// handleIncludeMacro notes an include that could not be spliced in
+func (p *ConfluencePlugin) handleIncludeMacro(n *html.Node) string {
+	if reason := attrValue(n, includeErrorAttr); reason != "" {
+		return fmt.Sprintf("<!-- Include skipped: %s -->", reason)
+	}
+	return "<!-- Included page unavailable -->"
+}
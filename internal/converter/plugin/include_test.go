@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestIncludeMacros(t *testing.T) {
+	pages := map[string]*model.ConfluencePage{
+		"Contacts": {ID: "20", Title: "Contacts", SpaceKey: "OPS", Content: model.ConfluenceContent{Storage: model.ContentStorage{
+			Value: `<p>Intro</p><ac:structured-macro ac:name="excerpt"><ac:parameter ac:name="hidden">true</ac:parameter><ac:rich-text-body><p>Call <strong>Ada</strong></p></ac:rich-text-body></ac:structured-macro><p>See <ac:link><ri:page ri:content-title="Rota" /></ac:link></p>`,
+		}}},
+		"Loop": {ID: "30", Title: "Loop", SpaceKey: "DOCS", Content: model.ConfluenceContent{Storage: model.ContentStorage{
+			Value: `<p>Looping</p><ac:structured-macro ac:name="include"><ac:parameter ac:name=""><ac:link><ri:page ri:content-title="Loop" /></ac:link></ac:parameter></ac:structured-macro>`,
+		}}},
+		"Rota": {ID: "21", Title: "Rota", SpaceKey: "OPS"},
+	}
+
+	ctrl := gomock.NewController(t)
+	client := mock_confluence.NewMockClient(ctrl)
+	client.EXPECT().FindContent(gomock.Any()).DoAndReturn(func(ref model.ContentRef) (*model.ConfluencePage, error) {
+		page := pages[ref.Title]
+		if page == nil || (ref.SpaceKey != page.SpaceKey) {
+			return nil, nil
+		}
+		return page, nil
+	}).AnyTimes()
+	client.EXPECT().GetPage(gomock.Any()).DoAndReturn(func(id string) (*model.ConfluencePage, error) {
+		for _, page := range pages {
+			if page.ID == id {
+				return page, nil
+			}
+		}
+		return nil, nil
+	}).AnyTimes()
+
+	plugin := NewConfluencePluginWithClient(client, nil, "assets")
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS"})
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "include whole page",
+			html: `<ac:structured-macro ac:name="include"><ac:parameter ac:name=""><ac:link><ri:page ri:space-key="OPS" ri:content-title="Contacts" /></ac:link></ac:parameter></ac:structured-macro>`,
+			want: "Intro\n\nSee [Rota](confluence://pageId/21)",
+		},
+		{
+			name: "excerpt include",
+			html: `<p>Before</p><ac:structured-macro ac:name="excerpt-include"><ac:parameter ac:name="nopanel">true</ac:parameter><ac:parameter ac:name=""><ac:link><ri:page ri:space-key="OPS" ri:content-title="Contacts" /></ac:link></ac:parameter></ac:structured-macro><p>After</p>`,
+			want: "Before\n\nCall **Ada**\n\nAfter",
+		},
+		{
+			name: "include cycle",
+			html: `<ac:structured-macro ac:name="include"><ac:parameter ac:name=""><ac:link><ri:page ri:content-title="Loop" /></ac:link></ac:parameter></ac:structured-macro>`,
+			want: "Looping\n\n<!-- Include skipped: Loop includes itself -->",
+		},
+		{
+			name: "missing page",
+			html: `<ac:structured-macro ac:name="include"><ac:parameter ac:name=""><ac:link><ri:page ri:content-title="Gone" /></ac:link></ac:parameter></ac:structured-macro>`,
+			want: "<!-- Include skipped: Gone not found -->",
+		},
+		{
+			name: "visible excerpt",
+			html: `<ac:structured-macro ac:name="excerpt"><ac:rich-text-body><p>Summary   of the page</p></ac:rich-text-body></ac:structured-macro>`,
+			want: "Summary of the page",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := plugin.Excerpt(); got != "Summary of the page" {
+		t.Fatalf("unexpected excerpt: %q", got)
+	}
+}
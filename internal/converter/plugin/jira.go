@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/jira"
+	"golang.org/x/net/html"
+)
+
+var issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-\d+$`)
+
+// SetJira configures the Jira sites and optional issue data used for jira macros
+func (p *ConfluencePlugin) SetJira(cfg jira.Config) {
+	p.jira = cfg
+}
+
+// handleJiraMacro converts single-issue macros to issue links and JQL macros to search links
+func (p *ConfluencePlugin) handleJiraMacro(n *html.Node) string {
+	key := macroParameter(n, "key")
+	jql := macroParameter(n, "jqlQuery")
+	if value := macroParameter(n, ""); value != "" {
+		if key == "" && issueKeyPattern.MatchString(value) {
+			key = value
+		} else if jql == "" {
+			jql = value
+		}
+	}
+
+	// Jira Cloud sites serve issues from the same host as Confluence
+	site := p.jira.SiteURL(macroParameter(n, "serverId"))
+	if site == "" {
+		site = p.baseURL
+	}
+
+	switch {
+	case key != "":
+		return p.formatJiraIssue(site, key)
+	case jql != "":
+		label := "Jira issues: " + jql
+		if strings.EqualFold(macroParameter(n, "count"), "true") {
+			label = "Jira issue count: " + jql
+		}
+		label = linkTextEscaper.Replace(label)
+		if site == "" {
+			return label
+		}
+		return fmt.Sprintf("[%s](%s)", label, jira.SearchURL(site, jql))
+	default:
+		return "<!-- Jira macro missing issue key or JQL query -->"
+	}
+}
+
+// formatJiraIssue links an issue key, followed by its summary and status when a provider knows them
+func (p *ConfluencePlugin) formatJiraIssue(site, key string) string {
+	result := key
+	if site != "" {
+		result = fmt.Sprintf("[%s](%s)", key, jira.IssueURL(site, key))
+	}
+
+	if p.jira.Provider == nil {
+		return result
+	}
+	issue, err := p.jira.Provider.GetIssue(key)
+	if err != nil || issue == nil {
+		return result
+	}
+	if issue.Summary != "" {
+		result += ": " + linkTextEscaper.Replace(issue.Summary)
+	}
+	if issue.Status != "" {
+		result += fmt.Sprintf(" (%s)", issue.Status)
+	}
+	return result
+}
+
+// macroParameter returns the trimmed value of a macro's direct ac:parameter child.
+// The unnamed default parameter has an empty name.
+func macroParameter(n *html.Node, name string) string {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:parameter" && attrValue(child, "ac:name") == name {
+			return strings.TrimSpace(textContent(child))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/jira"
+)
+
+type stubIssues map[string]*jira.Issue
+
+func (s stubIssues) GetIssue(key string) (*jira.Issue, error) {
+	return s[key], nil
+}
+
+func TestHandleJiraMacro(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     jira.Config
+		baseURL string
+		html    string
+		want    string
+	}{
+		{
+			name: "single issue on default site",
+			cfg:  jira.Config{BaseURL: "https://jira.example.com"},
+			html: `<p>Tracked in <ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">OPS-1</ac:parameter></ac:structured-macro></p>`,
+			want: "Tracked in [OPS-1](https://jira.example.com/browse/OPS-1)",
+		},
+		{
+			name: "server id mapping",
+			cfg:  jira.Config{BaseURL: "https://jira.example.com", Servers: map[string]string{"abc-123": "https://jira.internal"}},
+			html: `<p><ac:structured-macro ac:name="jira"><ac:parameter ac:name="server">System JIRA</ac:parameter><ac:parameter ac:name="serverId">abc-123</ac:parameter><ac:parameter ac:name="key">OPS-1</ac:parameter></ac:structured-macro></p>`,
+			want: "[OPS-1](https://jira.internal/browse/OPS-1)",
+		},
+		{
+			name:    "falls back to the confluence site",
+			baseURL: "https://example.atlassian.net",
+			html:    `<p><ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">OPS-1</ac:parameter></ac:structured-macro></p>`,
+			want:    "[OPS-1](https://example.atlassian.net/browse/OPS-1)",
+		},
+		{
+			name: "unknown site keeps the key",
+			html: `<p><ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">OPS-1</ac:parameter></ac:structured-macro></p>`,
+			want: "OPS-1",
+		},
+		{
+			name: "enriched issue",
+			cfg: jira.Config{
+				BaseURL:  "https://jira.example.com",
+				Provider: stubIssues{"OPS-1": {Key: "OPS-1", Summary: "Rotate [prod] credentials", Status: "In Progress"}},
+			},
+			html: `<p><ac:structured-macro ac:name="jira"><ac:parameter ac:name="key">OPS-1</ac:parameter></ac:structured-macro></p>`,
+			want: `[OPS-1](https://jira.example.com/browse/OPS-1): Rotate \[prod\] credentials (In Progress)`,
+		},
+		{
+			name: "jql table",
+			cfg:  jira.Config{BaseURL: "https://jira.example.com"},
+			html: `<ac:structured-macro ac:name="jira"><ac:parameter ac:name="columns">key,summary,status</ac:parameter><ac:parameter ac:name="maximumIssues">20</ac:parameter><ac:parameter ac:name="jqlQuery">project = OPS AND status = Open</ac:parameter></ac:structured-macro>`,
+			want: "[Jira issues: project = OPS AND status = Open](https://jira.example.com/issues/?jql=project+%3D+OPS+AND+status+%3D+Open)",
+		},
+		{
+			name: "jql count",
+			cfg:  jira.Config{BaseURL: "https://jira.example.com"},
+			html: `<ac:structured-macro ac:name="jira"><ac:parameter ac:name="count">true</ac:parameter><ac:parameter ac:name="jqlQuery">project = OPS</ac:parameter></ac:structured-macro>`,
+			want: "[Jira issue count: project = OPS](https://jira.example.com/issues/?jql=project+%3D+OPS)",
+		},
+		{
+			name: "default parameter key",
+			cfg:  jira.Config{BaseURL: "https://jira.example.com"},
+			html: `<p><ac:structured-macro ac:name="jira"><ac:parameter ac:name="">OPS-2</ac:parameter></ac:structured-macro></p>`,
+			want: "[OPS-2](https://jira.example.com/browse/OPS-2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewConfluencePlugin(nil, "assets")
+			plugin.SetJira(tt.cfg)
+			plugin.SetBaseURL(tt.baseURL)
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
+	"golang.org/x/net/html"
+)
+
+// SetLayoutStyle selects how the columns of page layouts and section macros are written
+func (p *ConfluencePlugin) SetLayoutStyle(style layout.Style) {
+	p.layoutStyle = style
+}
+
+// handleLayoutSection writes the cells of a page layout section as columns
+func (p *ConfluencePlugin) handleLayoutSection(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	var cells []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:layout-cell" {
+			cells = append(cells, child)
+		}
+	}
+
+	widths := layout.SectionWidths(attrValue(n, "ac:type"), len(cells))
+	columns := make([]layout.Column, len(cells))
+	for i, cell := range cells {
+		columns[i].Content = p.convertBody(ctx, cell)
+		if widths != nil {
+			columns[i].Width = widths[i]
+		}
+	}
+
+	if result := layout.Render(p.layoutStyle, columns); result != "" {
+		_, _ = w.WriteString("\n\n" + result + "\n\n")
+	}
+	return converter.RenderSuccess
+}
+
+// handleSectionMacro writes the column macros of a section macro as columns.
+// Content outside the columns joins the column before it.
+func (p *ConfluencePlugin) handleSectionMacro(ctx converter.Context, n *html.Node) string {
+	body := p.findRichTextBodyNode(n)
+	if body == nil {
+		return ""
+	}
+
+	var columns []layout.Column
+	for child := body.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:structured-macro" && attrValue(child, "ac:name") == "column" {
+			columns = append(columns, layout.Column{
+				Content: p.convertNestedHTML(ctx, child),
+				Width:   columnWidth(macroParameter(child, "width")),
+			})
+			continue
+		}
+
+		var buf strings.Builder
+		ctx.RenderNodes(ctx, &buf, child)
+		content := strings.TrimSpace(buf.String())
+		if content == "" {
+			continue
+		}
+		if len(columns) == 0 {
+			columns = append(columns, layout.Column{})
+		}
+		last := &columns[len(columns)-1]
+		last.Content = strings.TrimSpace(last.Content + "\n\n" + content)
+	}
+
+	if len(columns) == 0 {
+		return ""
+	}
+	return "\n\n" + layout.Render(p.layoutStyle, columns) + "\n\n"
+}
+
+// columnWidth reads the width of a column macro, which is in pixels unless a unit is given
+func columnWidth(width string) string {
+	if _, err := strconv.ParseFloat(width, 64); err == nil {
+		return width + "px"
+	}
+	return width
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
+)
+
+func TestLayouts(t *testing.T) {
+	pageLayout := `<ac:layout>` +
+		`<ac:layout-section ac:type="two_left_sidebar"><ac:layout-cell><p>Nav</p></ac:layout-cell><ac:layout-cell><p>Main</p><ul><li>point</li></ul></ac:layout-cell></ac:layout-section>` +
+		`<ac:layout-section ac:type="single"><ac:layout-cell><p>Footer</p></ac:layout-cell></ac:layout-section>` +
+		`</ac:layout>`
+	section := `<ac:structured-macro ac:name="section"><ac:rich-text-body>` +
+		`<ac:structured-macro ac:name="column"><ac:parameter ac:name="width">200</ac:parameter><ac:rich-text-body><p>A</p></ac:rich-text-body></ac:structured-macro>` +
+		`<ac:structured-macro ac:name="column"><ac:rich-text-body><p>B</p></ac:rich-text-body></ac:structured-macro>` +
+		`</ac:rich-text-body></ac:structured-macro>`
+	viewLayout := `<div class="contentLayout2"><div class="columnLayout two-right-sidebar" data-layout="two-right-sidebar">` +
+		`<div class="cell normal" data-type="normal"><div class="innerCell"><p>Main</p></div></div>` +
+		`<div class="cell aside" data-type="aside"><div class="innerCell"><p>Aside</p></div></div>` +
+		`</div></div>`
+	viewSection := `<div class="sectionColumnWrapper"><div class="sectionMacro"><div class="sectionMacroRow">` +
+		`<div class="columnMacro" style="width:30%;min-width:30%;max-width:30%;"><p>A</p></div>` +
+		`<div class="columnMacro"><p>B</p></div>` +
+		`</div></div></div>`
+
+	tests := []struct {
+		name  string
+		style layout.Style
+		html  string
+		want  string
+	}{
+		{name: "layout linear", style: layout.StyleLinear, html: pageLayout, want: "Nav\n\nMain\n\n- point\n\nFooter"},
+		{name: "layout separated", style: layout.StyleSeparated, html: pageLayout, want: "Nav\n\n<!-- column -->\n\nMain\n\n- point\n\nFooter"},
+		{
+			name:  "layout grid",
+			style: layout.StyleGrid,
+			html:  pageLayout,
+			want:  "<div style=\"display:flex\">\n<div style=\"flex:0 0 33%\">\n\nNav\n\n</div>\n<div style=\"flex:0 0 67%\">\n\nMain\n\n- point\n\n</div>\n</div>\n\nFooter",
+		},
+		{name: "section separated", style: layout.StyleSeparated, html: section, want: "A\n\n<!-- column -->\n\nB"},
+		{
+			name:  "section grid",
+			style: layout.StyleGrid,
+			html:  section,
+			want:  "<div style=\"display:flex\">\n<div style=\"flex:0 0 200px\">\n\nA\n\n</div>\n<div style=\"flex:1\">\n\nB\n\n</div>\n</div>",
+		},
+		{
+			name:  "view layout grid",
+			style: layout.StyleGrid,
+			html:  viewLayout,
+			want:  "<div style=\"display:flex\">\n<div style=\"flex:0 0 67%\">\n\nMain\n\n</div>\n<div style=\"flex:0 0 33%\">\n\nAside\n\n</div>\n</div>",
+		},
+		{name: "view section separated", style: layout.StyleSeparated, html: viewSection, want: "A\n\n<!-- column -->\n\nB"},
+		{
+			name:  "view section grid",
+			style: layout.StyleGrid,
+			html:  viewSection,
+			want:  "<div style=\"display:flex\">\n<div style=\"flex:0 0 30%\">\n\nA\n\n</div>\n<div style=\"flex:1\">\n\nB\n\n</div>\n</div>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewConfluencePlugin(nil, "assets")
+			plugin.SetLayoutStyle(tt.style)
+			view := NewViewPlugin("assets")
+			view.SetLayoutStyle(tt.style)
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin, view))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package plugin
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"golang.org/x/net/html"
+)
+
+// contentResources maps ac:link resource elements to the content type they reference
+var contentResources = map[string]string{
+	"ri:page":      model.ContentTypePage,
+	"ri:blog-post": model.ContentTypeBlogPost,
+	"ri:space":     model.ContentTypeSpace,
+}
+
+var linkTextEscaper = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+// SetBaseURL records the site URL used for links that cannot be resolved to a page ID
+func (p *ConfluencePlugin) SetBaseURL(baseURL string) {
+	p.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// fillEmptyLinkBodies gives content links without a body their default text before
+// whitespace is collapsed, so the spacing around links to bare page references survives
+func (p *ConfluencePlugin) fillEmptyLinkBodies(ctx converter.Context, doc *html.Node) {
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if n.Type != html.ElementNode || n.Data != "ac:link" {
+			return
+		}
+		if findElement(n, "ac:plain-text-link-body") != nil || findElement(n, "ac:link-body") != nil {
+			return
+		}
+
+		text := attrValue(n, "ac:anchor")
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if contentType, ok := contentResources[child.Data]; ok && child.Type == html.ElementNode {
+				ref := p.contentRef(child, contentType)
+				text = ref.Title
+				if text == "" {
+					text = ref.SpaceKey
+				}
+				break
+			}
+		}
+		if text == "" {
+			return
+		}
+
+		body := &html.Node{Type: html.ElementNode, Data: "ac:plain-text-link-body"}
+		body.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+		n.AppendChild(body)
+	}
+	walk(doc)
+}
+
+// handleContentLink converts ac:link elements pointing at pages, blog posts, spaces
+// or anchors. Resolved targets are written as confluence://pageId/<id> links so the
+// link rewriting pass can point them at exported files. It reports whether n was handled.
+func (p *ConfluencePlugin) handleContentLink(ctx converter.Context, w converter.Writer, n *html.Node) bool {
+	anchor := attrValue(n, "ac:anchor")
+
+	var resource *html.Node
+	contentType := ""
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if t, ok := contentResources[child.Data]; ok && child.Type == html.ElementNode {
+			resource, contentType = child, t
+			break
+		}
+	}
+	if resource == nil && anchor == "" {
+		return false
+	}
+
+	text := p.linkBody(ctx, n)
+	target := ""
+	if resource == nil {
+		// Anchor on the current page
+		target = "#" + url.PathEscape(p.anchorID(p.currentTitle(), anchor))
+	} else {
+		target = p.resolveContentLink(p.contentRef(resource, contentType), anchor)
+	}
+
+	if target == "" {
+		_, _ = w.WriteString(text)
+	} else {
+		_, _ = fmt.Fprintf(w, "[%s](%s)", text, target)
+	}
+	return true
+}
+
+// contentRef reads the referenced space and title, defaulting to the current page's space
+func (p *ConfluencePlugin) contentRef(resource *html.Node, contentType string) model.ContentRef {
+	ref := model.ContentRef{
+		Type:       contentType,
+		SpaceKey:   attrValue(resource, "ri:space-key"),
+		Title:      attrValue(resource, "ri:content-title"),
+		PostingDay: attrValue(resource, "ri:posting-day"),
+	}
+	if ref.SpaceKey == "" && p.currentPage != nil {
+		ref.SpaceKey = p.currentPage.SpaceKey
+	}
+	return ref
+}
+
+// resolveContentLink finds the link target for a content reference, falling back
+// to a Confluence URL built from the space and title when the lookup fails
+func (p *ConfluencePlugin) resolveContentLink(ref model.ContentRef, anchor string) string {
+	// fragment builds the anchor for the target page, in the style used for converted
+	// pages or, for Confluence URLs, in Confluence's own style
+	fragment := func(style HeadingIDStyle, title string) string {
+		if anchor == "" {
+			return ""
+		}
+		return "#" + url.PathEscape(AnchorID(style, title, anchor))
+	}
+
+	if current := p.currentPage; current != nil && ref.Type == model.ContentTypePage &&
+		ref.Title == current.Title && ref.SpaceKey == current.SpaceKey {
+		return "confluence://pageId/" + current.ID + fragment(p.headingIDs, current.Title)
+	}
+
+	if p.client != nil {
+		if page, err := p.client.FindContent(ref); err == nil && page != nil {
+			return "confluence://pageId/" + page.ID + fragment(p.headingIDs, page.Title)
+		}
+	}
+
+	if p.baseURL == "" || ref.SpaceKey == "" {
+		return ""
+	}
+	switch ref.Type {
+	case model.ContentTypeSpace:
+		return fmt.Sprintf("%s/wiki/spaces/%s", p.baseURL, url.PathEscape(ref.SpaceKey))
+	case model.ContentTypeBlogPost:
+		return fmt.Sprintf("%s/wiki/display/%s/%s/%s%s", p.baseURL, url.PathEscape(ref.SpaceKey), ref.PostingDay, url.QueryEscape(ref.Title), fragment(HeadingIDsConfluence, ref.Title))
+	default:
+		return fmt.Sprintf("%s/wiki/display/%s/%s%s", p.baseURL, url.PathEscape(ref.SpaceKey), url.QueryEscape(ref.Title), fragment(HeadingIDsConfluence, ref.Title))
+	}
+}
+
+// linkBody returns the Markdown for an ac:link's plain-text or rich body. The body
+// may be nested in the resource element, since the HTML parser does not honour
+// self-closing ri: tags.
+func (p *ConfluencePlugin) linkBody(ctx converter.Context, n *html.Node) string {
+	if body := findElement(n, "ac:plain-text-link-body"); body != nil {
+		return linkTextEscaper.Replace(strings.TrimSpace(textContent(body)))
+	}
+	if body := findElement(n, "ac:link-body"); body != nil {
+		return renderChildren(ctx, body)
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestHandleContentLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_confluence.NewMockClient(ctrl)
+	client.EXPECT().FindContent(model.ContentRef{Type: model.ContentTypePage, SpaceKey: "OPS", Title: "Runbook"}).
+		Return(&model.ConfluencePage{ID: "42", Title: "Runbook", SpaceKey: "OPS"}, nil).AnyTimes()
+	client.EXPECT().FindContent(model.ContentRef{Type: model.ContentTypeSpace, SpaceKey: "OPS"}).
+		Return(&model.ConfluencePage{ID: "7", Title: "Operations", SpaceKey: "OPS"}, nil).AnyTimes()
+	client.EXPECT().FindContent(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	plugin := NewConfluencePluginWithClient(client, nil, "assets")
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS"})
+	plugin.SetBaseURL("https://example.atlassian.net/")
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "page with plain text body",
+			html: `<p><ac:link><ri:page ri:content-title="Runbook" ri:space-key="OPS" /><ac:plain-text-link-body>the runbook</ac:plain-text-link-body></ac:link></p>`,
+			want: "[the runbook](confluence://pageId/42)",
+		},
+		{
+			name: "page with anchor and rich body",
+			html: `<p><ac:link ac:anchor="Escalation"><ri:page ri:content-title="Runbook" ri:space-key="OPS" /><ac:link-body><strong>escalate</strong></ac:link-body></ac:link></p>`,
+			want: "[**escalate**](confluence://pageId/42#Runbook-Escalation)",
+		},
+		{
+			name: "current page defaults to its space",
+			html: `<p><ac:link><ri:page ri:content-title="Home" /></ac:link></p>`,
+			want: "[Home](confluence://pageId/1)",
+		},
+		{
+			name: "space links to home page",
+			html: `<p><ac:link><ri:space ri:space-key="OPS" /></ac:link></p>`,
+			want: "[OPS](confluence://pageId/7)",
+		},
+		{
+			name: "unresolved page falls back to URL",
+			html: `<p><ac:link><ri:page ri:content-title="Old [draft] page" /></ac:link></p>`,
+			want: `[Old \[draft\] page](https://example.atlassian.net/wiki/display/DOCS/Old+%5Bdraft%5D+page)`,
+		},
+		{
+			name: "unresolved blog post falls back to URL",
+			html: `<p><ac:link><ri:blog-post ri:content-title="Launch" ri:posting-day="2024/05/01" /></ac:link></p>`,
+			want: "[Launch](https://example.atlassian.net/wiki/display/DOCS/2024/05/01/Launch)",
+		},
+		{
+			name: "anchor on current page",
+			html: `<p><ac:link ac:anchor="Setup"><ac:plain-text-link-body>setup</ac:plain-text-link-body></ac:link></p>`,
+			want: "[setup](#Home-Setup)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleContentLinkWithoutClient(t *testing.T) {
+	plugin := NewConfluencePlugin(nil, "assets")
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS"})
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	got, err := conv.ConvertString(`<p>See <ac:link><ri:page ri:content-title="Runbook" /></ac:link></p>`)
+	if err != nil {
+		t.Fatalf("convert error: %v", err)
+	}
+	if strings.TrimSpace(got) != "See Runbook" {
+		t.Fatalf("expected link text only, got %q", got)
+	}
+}
@@ -0,0 +1,220 @@
+package plugin
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// listingMacro describes a macro that lists the results of a content search
+type listingMacro struct {
+	label       string // Name used in comments
+	contentType string // CQL types searched when the macro has no type parameter
+	sort        string // Sort used when the macro has no sort parameter
+	max         int    // Results listed when the macro has no max parameter
+}
+
+// listingMacros are evaluated at export time by translating their parameters into CQL
+var listingMacros = map[string]listingMacro{
+	"contentbylabel":   {label: "Content by Label", contentType: "page,blogpost", sort: "modified", max: 5},
+	"recently-updated": {label: "Recently Updated", contentType: "page,blogpost", sort: "modified", max: 15},
+	"blog-posts":       {label: "Blog Posts", contentType: "blogpost", sort: "creation", max: 15},
+}
+
+// blogPostTimeFrame matches the time parameter of blog-posts macros, such as 7d or 2w
+var blogPostTimeFrame = regexp.MustCompile(`^\d+[mhdw]$`)
+
+var cqlEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// handleListingMacro lists the pages and blog posts a listing macro shows as
+// confluence:// links. The list is a snapshot of the results at export time.
+func (p *ConfluencePlugin) handleListingMacro(n *html.Node, macro listingMacro) string {
+	if p.client == nil {
+		return fmt.Sprintf("<!-- %s -->", macro.label)
+	}
+
+	limit := macro.max
+	for _, name := range []string{"max", "maxResults"} {
+		if value, err := strconv.Atoi(macroParameter(n, name)); err == nil && value > 0 {
+			limit = value
+			break
+		}
+	}
+
+	query := p.listingQuery(n, macro)
+	pages, err := p.client.SearchContent(query, limit)
+	if err != nil {
+		log.Printf("Failed to search content for %s macro: %v", macro.label, err)
+		return fmt.Sprintf("<!-- %s: search failed -->", macro.label)
+	}
+	if len(pages) == 0 {
+		return fmt.Sprintf("<!-- %s: no results at export time -->", macro.label)
+	}
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "\n\n<!-- %s: snapshot of search results at export time -->\n", macro.label)
+	for _, page := range pages {
+		_, _ = fmt.Fprintf(&b, "- [%s](confluence://pageId/%s)\n", linkTextEscaper.Replace(page.Title), page.ID)
+	}
+	return strings.TrimSuffix(b.String(), "\n") + "\n\n"
+}
+
+// listingQuery translates the labels, spaces, types, time frame and sort of a
+// listing macro into CQL. A cql parameter, written by the Cloud editor, is used
+// as the filter in place of the other parameters, keeping its own order if it has one.
+func (p *ConfluencePlugin) listingQuery(n *html.Node, macro listingMacro) string {
+	var clauses []string
+	order := ""
+	if cql := strings.TrimSpace(macroParameter(n, "cql")); cql != "" {
+		if p.currentPage != nil {
+			cql = strings.ReplaceAll(cql, "currentSpace()", cqlQuote(p.currentPage.SpaceKey))
+		}
+		if i := strings.LastIndex(strings.ToLower(cql), "order by"); i >= 0 {
+			cql, order = strings.TrimSpace(cql[:i]), cql[i:]
+		}
+		if cql != "" {
+			clauses = append(clauses, "("+cql+")")
+		}
+	} else {
+		clauses = append(clauses, p.spaceClauses(firstParameter(n, "spaces", "space", "spaceKey"))...)
+		clauses = append(clauses, labelClauses(firstParameter(n, "labels", "label"), macroParameter(n, "operator"))...)
+
+		contentType := macro.contentType
+		if value := firstParameter(n, "types", "type"); value != "" {
+			contentType = value
+		}
+		clauses = append(clauses, inClause("type", listValues(contentType)))
+
+		if frame := strings.TrimSpace(macroParameter(n, "time")); blogPostTimeFrame.MatchString(frame) {
+			clauses = append(clauses, fmt.Sprintf(`created >= now("-%s")`, frame))
+		}
+	}
+	if strings.EqualFold(macroParameter(n, "excludeCurrent"), "true") && p.currentPage != nil {
+		clauses = append(clauses, "id != "+p.currentPage.ID)
+	}
+
+	if order != "" {
+		return strings.TrimSpace(strings.Join(clauses, " and ") + " " + order)
+	}
+
+	sort := strings.ToLower(macroParameter(n, "sort"))
+	if sort == "" {
+		sort = macro.sort
+	}
+	field, descending := "lastmodified", true
+	switch sort {
+	case "title":
+		field, descending = "title", false
+	case "creation":
+		field = "created"
+	}
+	if strings.EqualFold(macroParameter(n, "reverse"), "true") {
+		descending = !descending
+	}
+	direction := "asc"
+	if descending {
+		direction = "desc"
+	}
+
+	return strings.Join(clauses, " and ") + " order by " + field + " " + direction
+}
+
+// spaceClauses restricts a search to the listed spaces. @self, the default,
+// is the current page's space and @all lifts the restriction.
+func (p *ConfluencePlugin) spaceClauses(spaces string) []string {
+	var keys []string
+	var spaceTypes []string
+	values := listValues(spaces)
+	if len(values) == 0 {
+		values = []string{"@self"}
+	}
+	for _, value := range values {
+		switch value {
+		case "@all", "*":
+			return nil
+		case "@self":
+			if p.currentPage != nil && p.currentPage.SpaceKey != "" {
+				keys = append(keys, p.currentPage.SpaceKey)
+			}
+		case "@personal", "@global":
+			spaceTypes = append(spaceTypes, strings.TrimPrefix(value, "@"))
+		default:
+			keys = append(keys, value)
+		}
+	}
+
+	var clauses []string
+	if len(keys) > 0 {
+		clauses = append(clauses, inClause("space", keys))
+	}
+	if len(spaceTypes) > 0 {
+		clauses = append(clauses, inClause("space.type", spaceTypes))
+	}
+	if len(clauses) > 1 {
+		return []string{"(" + strings.Join(clauses, " or ") + ")"}
+	}
+	return clauses
+}
+
+// labelClauses matches any of the labels, or all of them with the AND operator.
+// Labels prefixed with + are always required and those prefixed with - excluded.
+func labelClauses(labels, operator string) []string {
+	var optional, clauses []string
+	for _, label := range listValues(labels) {
+		switch {
+		case strings.HasPrefix(label, "+"):
+			clauses = append(clauses, "label = "+cqlQuote(label[1:]))
+		case strings.HasPrefix(label, "-"):
+			clauses = append(clauses, "label != "+cqlQuote(label[1:]))
+		default:
+			optional = append(optional, label)
+		}
+	}
+
+	if strings.EqualFold(operator, "AND") {
+		for _, label := range optional {
+			clauses = append(clauses, "label = "+cqlQuote(label))
+		}
+	} else if len(optional) > 0 {
+		clauses = append(clauses, inClause("label", optional))
+	}
+	return clauses
+}
+
+// inClause matches a CQL field against one or more values
+func inClause(field string, values []string) string {
+	if len(values) == 1 {
+		return field + " = " + cqlQuote(values[0])
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = cqlQuote(value)
+	}
+	return field + " in (" + strings.Join(quoted, ", ") + ")"
+}
+
+// cqlQuote writes a CQL string literal
+func cqlQuote(s string) string {
+	return `"` + cqlEscaper.Replace(s) + `"`
+}
+
+// listValues splits a comma or space separated macro parameter
+func listValues(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// firstParameter returns the first of the named macro parameters that is set
+func firstParameter(n *html.Node, names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(macroParameter(n, name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
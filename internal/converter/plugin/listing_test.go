@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestListingMacros(t *testing.T) {
+	results := []*model.ConfluencePage{
+		{ID: "11", Title: "Runbook"},
+		{ID: "12", Title: "On-call [rota]"},
+	}
+
+	tests := []struct {
+		name   string
+		html   string
+		query  string
+		limit  int
+		result []*model.ConfluencePage
+		err    error
+		want   string
+	}{
+		{
+			name:   "content by label in the current space",
+			html:   `<ac:structured-macro ac:name="contentbylabel"><ac:parameter ac:name="labels">ops,runbook</ac:parameter><ac:parameter ac:name="max">10</ac:parameter><ac:parameter ac:name="sort">title</ac:parameter></ac:structured-macro><p>After</p>`,
+			query:  `space = "DOCS" and label in ("ops", "runbook") and type in ("page", "blogpost") order by title asc`,
+			limit:  10,
+			result: results,
+			want:   "<!-- Content by Label: snapshot of search results at export time -->\n- [Runbook](confluence://pageId/11)\n- [On-call \\[rota\\]](confluence://pageId/12)\n\nAfter",
+		},
+		{
+			name:   "all labels in other spaces",
+			html:   `<ac:structured-macro ac:name="contentbylabel"><ac:parameter ac:name="labels">ops +team -draft</ac:parameter><ac:parameter ac:name="operator">AND</ac:parameter><ac:parameter ac:name="spaces">OPS,@personal</ac:parameter><ac:parameter ac:name="type">page</ac:parameter><ac:parameter ac:name="excludeCurrent">true</ac:parameter></ac:structured-macro>`,
+			query:  `(space = "OPS" or space.type = "personal") and label = "team" and label != "draft" and label = "ops" and type = "page" and id != 1 order by lastmodified desc`,
+			limit:  5,
+			result: results[:1],
+			want:   "<!-- Content by Label: snapshot of search results at export time -->\n- [Runbook](confluence://pageId/11)",
+		},
+		{
+			name:   "cloud cql parameter",
+			html:   `<ac:structured-macro ac:name="contentbylabel"><ac:parameter ac:name="cql">label = "ops" and space = currentSpace()</ac:parameter></ac:structured-macro>`,
+			query:  `(label = "ops" and space = "DOCS") order by lastmodified desc`,
+			limit:  5,
+			result: results[:1],
+			want:   "<!-- Content by Label: snapshot of search results at export time -->\n- [Runbook](confluence://pageId/11)",
+		},
+		{
+			name:   "cloud cql parameter with its own order",
+			html:   `<ac:structured-macro ac:name="contentbylabel"><ac:parameter ac:name="cql">label = "ops" ORDER BY title</ac:parameter><ac:parameter ac:name="excludeCurrent">true</ac:parameter></ac:structured-macro>`,
+			query:  `(label = "ops") and id != 1 ORDER BY title`,
+			limit:  5,
+			result: results[:1],
+			want:   "<!-- Content by Label: snapshot of search results at export time -->\n- [Runbook](confluence://pageId/11)",
+		},
+		{
+			name:   "recently updated across spaces",
+			html:   `<ac:structured-macro ac:name="recently-updated"><ac:parameter ac:name="spaces">@all</ac:parameter><ac:parameter ac:name="types">page</ac:parameter></ac:structured-macro>`,
+			query:  `type = "page" order by lastmodified desc`,
+			limit:  15,
+			result: results[:1],
+			want:   "<!-- Recently Updated: snapshot of search results at export time -->\n- [Runbook](confluence://pageId/11)",
+		},
+		{
+			name:  "blog posts in a time frame",
+			html:  `<ac:structured-macro ac:name="blog-posts"><ac:parameter ac:name="time">2w</ac:parameter><ac:parameter ac:name="reverse">true</ac:parameter></ac:structured-macro>`,
+			query: `space = "DOCS" and type = "blogpost" and created >= now("-2w") order by created asc`,
+			limit: 15,
+			want:  "<!-- Blog Posts: no results at export time -->",
+		},
+		{
+			name:  "search failure",
+			html:  `<ac:structured-macro ac:name="recently-updated" />`,
+			query: `space = "DOCS" and type in ("page", "blogpost") order by lastmodified desc`,
+			limit: 15,
+			err:   errors.New("boom"),
+			want:  "<!-- Recently Updated: search failed -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := mock_confluence.NewMockClient(ctrl)
+			client.EXPECT().SearchContent(tt.query, tt.limit).Return(tt.result, tt.err)
+
+			plugin := NewConfluencePluginWithClient(client, nil, "assets")
+			plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS"})
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	plugin := NewConfluencePlugin(nil, "assets")
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+	got, err := conv.ConvertString(`<ac:structured-macro ac:name="blog-posts" />`)
+	if err != nil {
+		t.Fatalf("convert error: %v", err)
+	}
+	if strings.TrimSpace(got) != "<!-- Blog Posts -->" {
+		t.Fatalf("unexpected markdown without client: %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// MathStyle selects the delimiters LaTeX from math macros is written with
+type MathStyle string
+
+const (
+	// MathDollar writes $...$ and $$...$$, understood by GitHub, KaTeX and MathJax
+	MathDollar MathStyle = "dollar"
+	// MathLaTeX writes \(...\) and \[...\], MathJax's default delimiters
+	MathLaTeX MathStyle = "latex"
+	// MathGFM writes $`...`$ and ```math fences, which GitHub never mistakes for currency
+	MathGFM MathStyle = "gfm"
+)
+
+// mathMacros lists the math macros and whether each is shown inline
+var mathMacros = map[string]bool{
+	"mathinline": true,
+	"mathblock":  false,
+	"latex":      false,
+	"easy-math":  false,
+}
+
+// ParseMathStyle validates a math style name
+func ParseMathStyle(s string) (MathStyle, error) {
+	switch style := MathStyle(strings.ToLower(strings.TrimSpace(s))); style {
+	case MathDollar, MathLaTeX, MathGFM:
+		return style, nil
+	case "":
+		return MathDollar, nil
+	default:
+		return "", fmt.Errorf("unsupported math style: %s (use dollar, latex or gfm)", s)
+	}
+}
+
+// SetMathStyle selects the delimiters for math macros
+func (p *ConfluencePlugin) SetMathStyle(style MathStyle) {
+	p.mathStyle = style
+}
+
+// handleMathMacro writes the LaTeX of a math macro as inline or display math. The
+// formula is either the body parameter or the plain-text body.
+func (p *ConfluencePlugin) handleMathMacro(n *html.Node, inline bool) string {
+	formula := macroParameter(n, "body")
+	if formula == "" {
+		if body := findElement(n, "ac:plain-text-body"); body != nil {
+			formula = strings.TrimSpace(textContent(body))
+		}
+	}
+	if formula == "" {
+		return ""
+	}
+
+	if inline {
+		// Inline math cannot span lines
+		formula = strings.Join(strings.Fields(formula), " ")
+		switch p.mathStyle {
+		case MathLaTeX:
+			return `\(` + formula + `\)`
+		case MathGFM:
+			return "$`" + formula + "`$"
+		default:
+			return "$" + formula + "$"
+		}
+	}
+
+	switch p.mathStyle {
+	case MathLaTeX:
+		return "\\[\n" + formula + "\n\\]\n"
+	case MathGFM:
+		return "```math\n" + formula + "\n```\n"
+	default:
+		return "$$\n" + formula + "\n$$\n"
+	}
+}
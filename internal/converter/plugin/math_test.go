@@ -0,0 +1,15 @@
+package plugin
+
+import "testing"
+
+func TestParseMathStyle(t *testing.T) {
+	if style, err := ParseMathStyle(""); err != nil || style != MathDollar {
+		t.Fatalf("expected dollar default, got %q, %v", style, err)
+	}
+	if style, err := ParseMathStyle("GFM"); err != nil || style != MathGFM {
+		t.Fatalf("expected gfm, got %q, %v", style, err)
+	}
+	if _, err := ParseMathStyle("asciimath"); err == nil {
+		t.Fatal("expected error for unknown style")
+	}
+}
@@ -0,0 +1,283 @@
+package plugin
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"golang.org/x/net/html"
+)
+
+// fileViewerMacros preview an attachment inside the page
+var fileViewerMacros = map[string]bool{
+	"view-file": true,
+	"viewpdf":   true,
+	"viewdoc":   true,
+	"viewxls":   true,
+	"viewppt":   true,
+}
+
+var (
+	videoExtensions = []string{".mp4", ".webm", ".ogv", ".mov", ".m4v"}
+	audioExtensions = []string{".mp3", ".wav", ".ogg", ".m4a", ".flac"}
+)
+
+// videoEmbeds map the share URLs of video sites to their embeddable players
+var videoEmbeds = []struct {
+	pattern *regexp.Regexp
+	embed   string
+}{
+	{regexp.MustCompile(`^https?://(?:www\.|m\.)?youtube\.com/(?:watch\?(?:.*&)?v=|embed/|shorts/)([\w-]+)`), "https://www.youtube.com/embed/%s"},
+	{regexp.MustCompile(`^https?://youtu\.be/([\w-]+)`), "https://www.youtube.com/embed/%s"},
+	{regexp.MustCompile(`^https?://(?:www\.)?loom\.com/(?:share|embed)/(\w+)`), "https://www.loom.com/embed/%s"},
+	{regexp.MustCompile(`^https?://(?:www\.)?vimeo\.com/(\d+)`), "https://player.vimeo.com/video/%s"},
+}
+
+var tableCellEscaper = strings.NewReplacer("[", `\[`, "]", `\]`, "|", `\|`)
+
+// SetMediaEmbeds writes multimedia and widget macros as HTML5 <video> and <iframe>
+// embeds instead of links
+func (p *ConfluencePlugin) SetMediaEmbeds(enabled bool) {
+	p.mediaEmbeds = enabled
+}
+
+// handleFileViewerMacro links to the attachment a file viewer macro previews
+func (p *ConfluencePlugin) handleFileViewerMacro(n *html.Node) string {
+	attachment := parameterAttachment(n, "name")
+	filename := attrValue(attachment, "ri:filename")
+	if filename == "" {
+		return "<!-- File viewer macro missing attachment -->"
+	}
+
+	link := p.attachmentLink(attachment)
+	if link == "" {
+		return fmt.Sprintf("<!-- Attachment %s unavailable -->", filename)
+	}
+	return fmt.Sprintf("[%s](%s)", linkTextEscaper.Replace(filename), link)
+}
+
+// handleMultimediaMacro links to an attached video or audio file, or embeds it
+// in an HTML5 player when media embeds are enabled
+func (p *ConfluencePlugin) handleMultimediaMacro(n *html.Node) string {
+	attachment := parameterAttachment(n, "name")
+	filename := attrValue(attachment, "ri:filename")
+	if filename == "" {
+		return "<!-- Multimedia macro missing attachment -->"
+	}
+
+	link := p.attachmentLink(attachment)
+	if link == "" {
+		return fmt.Sprintf("<!-- Attachment %s unavailable -->", filename)
+	}
+	if p.mediaEmbeds {
+		if embed := mediaElement(link, macroParameter(n, "width"), macroParameter(n, "height"), strings.EqualFold(macroParameter(n, "autostart"), "true")); embed != "" {
+			return "\n\n" + embed + "\n\n"
+		}
+	}
+	return fmt.Sprintf("[%s](%s)", linkTextEscaper.Replace(filename), link)
+}
+
+// handleWidgetMacro links to the URL of a widget connector macro, or embeds
+// YouTube, Loom and Vimeo videos and video files when media embeds are enabled
+func (p *ConfluencePlugin) handleWidgetMacro(n *html.Node) string {
+	target := strings.TrimSpace(macroParameter(n, "url"))
+	if resource := parameterElement(n, "url", "ri:url"); resource != nil {
+		target = attrValue(resource, "ri:value")
+	}
+	if target == "" {
+		return "<!-- Widget macro missing URL -->"
+	}
+
+	if p.mediaEmbeds {
+		width, height := macroParameter(n, "width"), macroParameter(n, "height")
+		for _, site := range videoEmbeds {
+			if matches := site.pattern.FindStringSubmatch(target); matches != nil {
+				return "\n\n" + iframeElement(fmt.Sprintf(site.embed, matches[1]), width, height) + "\n\n"
+			}
+		}
+		if embed := mediaElement(target, width, height, false); embed != "" {
+			return "\n\n" + embed + "\n\n"
+		}
+	}
+	return fmt.Sprintf("[%s](%s)", linkTextEscaper.Replace(target), target)
+}
+
+// handleAttachmentsMacro lists the current page's attachments in a table of
+// links and sizes, honouring the macro's patterns, sortBy and sortOrder
+func (p *ConfluencePlugin) handleAttachmentsMacro(n *html.Node) string {
+	if p.currentPage == nil {
+		return "<!-- Attachments -->"
+	}
+
+	var patterns []*regexp.Regexp
+	for _, pattern := range strings.Split(macroParameter(n, "patterns"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)^(?:" + pattern + ")$")
+		if err != nil {
+			log.Printf("Ignoring invalid attachments pattern %q: %v", pattern, err)
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+
+	var list []model.ConfluenceAttachment
+	for _, attachment := range p.currentPage.Attachments {
+		if len(patterns) == 0 || slices.ContainsFunc(patterns, func(re *regexp.Regexp) bool { return re.MatchString(attachment.Title) }) {
+			list = append(list, attachment)
+		}
+	}
+	if len(list) == 0 {
+		return "<!-- No attachments -->"
+	}
+
+	switch strings.ToLower(macroParameter(n, "sortBy")) {
+	case "name":
+		slices.SortStableFunc(list, func(a, b model.ConfluenceAttachment) int {
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		})
+	case "size":
+		slices.SortStableFunc(list, func(a, b model.ConfluenceAttachment) int {
+			return cmp.Compare(a.FileSize, b.FileSize)
+		})
+	}
+	if strings.EqualFold(macroParameter(n, "sortOrder"), "descending") {
+		slices.Reverse(list)
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n| File | Size |\n|---|---|\n")
+	for _, attachment := range list {
+		name := tableCellEscaper.Replace(attachment.Title)
+		if link := p.pageAttachmentLink(attachment.Title); link != "" {
+			name = fmt.Sprintf("[%s](%s)", name, link)
+		}
+		_, _ = fmt.Fprintf(&b, "| %s | %s |\n", name, formatFileSize(attachment.FileSize))
+	}
+	return b.String() + "\n"
+}
+
+// attachmentLink resolves an ri:attachment to the downloaded file, or to its
+// download URL on the site when it was not saved or belongs to another page
+func (p *ConfluencePlugin) attachmentLink(attachment *html.Node) string {
+	filename := attrValue(attachment, "ri:filename")
+	for child := attachment.FirstChild; child != nil; child = child.NextSibling {
+		contentType, ok := contentResources[child.Data]
+		if !ok || child.Type != html.ElementNode || contentType == model.ContentTypeSpace {
+			continue
+		}
+		if p.client == nil {
+			return ""
+		}
+		found, err := p.client.FindContent(p.contentRef(child, contentType))
+		if err != nil || found == nil {
+			return ""
+		}
+		return p.remoteAttachmentLink(found.ID, filename)
+	}
+
+	return p.pageAttachmentLink(filename)
+}
+
+// pageAttachmentLink saves an attachment of the page being converted to the image
+// folder and links to it, falling back to the attachment's download URL
+func (p *ConfluencePlugin) pageAttachmentLink(filename string) string {
+	if p.currentPage == nil {
+		return ""
+	}
+	if p.attachmentResolver != nil && p.imageFolder != "" {
+		if saved := p.fetchAsset(filename, 0, filename); saved != "" {
+			return p.assetPath(saved)
+		}
+	}
+	return p.remoteAttachmentLink(p.currentPage.ID, filename)
+}
+
+// remoteAttachmentLink builds the download URL of an attachment on the site
+func (p *ConfluencePlugin) remoteAttachmentLink(pageID, filename string) string {
+	if p.baseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/wiki/download/attachments/%s/%s", p.baseURL, pageID, url.PathEscape(filename))
+}
+
+// parameterAttachment returns the ri:attachment referenced by a macro parameter
+func parameterAttachment(n *html.Node, name string) *html.Node {
+	return parameterElement(n, name, "ri:attachment")
+}
+
+// parameterElement returns the first element named tag inside a macro parameter
+func parameterElement(n *html.Node, name, tag string) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:parameter" && attrValue(child, "ac:name") == name {
+			return findElement(child, tag)
+		}
+	}
+	return nil
+}
+
+// mediaElement writes an HTML5 <video> or <audio> player for a media file URL,
+// or returns an empty string for other files
+func mediaElement(src, width, height string, autoplay bool) string {
+	extension := strings.ToLower(path.Ext(strings.SplitN(src, "?", 2)[0]))
+	tag := ""
+	switch {
+	case slices.Contains(videoExtensions, extension):
+		tag = "video"
+	case slices.Contains(audioExtensions, extension):
+		tag = "audio"
+	default:
+		return ""
+	}
+
+	attrs := fmt.Sprintf(`src="%s" controls`, html.EscapeString(src))
+	if tag == "video" {
+		attrs += sizeAttributes(width, height)
+	}
+	if autoplay {
+		attrs += " autoplay"
+	}
+	return fmt.Sprintf("<%s %s></%s>", tag, attrs, tag)
+}
+
+// iframeElement writes an embedded player, 640x360 unless the macro sets a size
+func iframeElement(src, width, height string) string {
+	if width == "" && height == "" {
+		width, height = "640", "360"
+	}
+	return fmt.Sprintf(`<iframe src="%s"%s frameborder="0" allowfullscreen></iframe>`, html.EscapeString(src), sizeAttributes(width, height))
+}
+
+// sizeAttributes writes width and height attributes from macro parameters such as 400 or 400px
+func sizeAttributes(width, height string) string {
+	var attrs string
+	if width = strings.TrimSuffix(strings.TrimSpace(width), "px"); width != "" {
+		attrs += fmt.Sprintf(` width="%s"`, html.EscapeString(width))
+	}
+	if height = strings.TrimSuffix(strings.TrimSpace(height), "px"); height != "" {
+		attrs += fmt.Sprintf(` height="%s"`, html.EscapeString(height))
+	}
+	return attrs
+}
+
+// formatFileSize writes a byte count in binary units, such as 1.5 MB
+func formatFileSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, unit := float64(size)/1024, "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if value < 1024 {
+			break
+		}
+		value, unit = value/1024, next
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
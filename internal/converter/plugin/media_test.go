@@ -0,0 +1,157 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	mock_attachments "github.com/jackchuka/confluence-md/internal/converter/plugin/attachments/mock"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestMediaMacros(t *testing.T) {
+	files := map[string]string{
+		"Handbook.pdf": "pdf",
+		"Demo.mp4":     "mp4",
+		"notes|v2.txt": "txt",
+	}
+	page := &model.ConfluencePage{
+		ID:       "1",
+		Title:    "Home",
+		SpaceKey: "DOCS",
+		Attachments: []model.ConfluenceAttachment{
+			{Title: "Handbook.pdf", FileSize: 2_500_000},
+			{Title: "notes|v2.txt", FileSize: 512},
+			{Title: "Offline.zip", FileSize: 1536},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		html       string
+		embeds     bool
+		want       string
+		wantAssets []string
+	}{
+		{
+			name:       "pdf viewer",
+			html:       `<ac:structured-macro ac:name="viewpdf"><ac:parameter ac:name="name"><ri:attachment ri:filename="Handbook.pdf" /></ac:parameter></ac:structured-macro>`,
+			want:       "[Handbook.pdf](assets%2FHandbook.pdf)",
+			wantAssets: []string{"Handbook.pdf"},
+		},
+		{
+			name: "file viewer for an attachment that was not saved",
+			html: `<ac:structured-macro ac:name="view-file"><ac:parameter ac:name="name"><ri:attachment ri:filename="Offline.zip" /></ac:parameter></ac:structured-macro>`,
+			want: "[Offline.zip](https://example.atlassian.net/wiki/download/attachments/1/Offline.zip)",
+		},
+		{
+			name: "attachments table",
+			html: `<ac:structured-macro ac:name="attachments"><ac:parameter ac:name="sortBy">size</ac:parameter><ac:parameter ac:name="sortOrder">descending</ac:parameter></ac:structured-macro>`,
+			want: "| File | Size |\n|---|---|\n" +
+				"| [Handbook.pdf](assets%2FHandbook.pdf) | 2.4 MB |\n" +
+				"| [Offline.zip](https://example.atlassian.net/wiki/download/attachments/1/Offline.zip) | 1.5 KB |\n" +
+				"| [notes\\|v2.txt](assets%2Fnotes%7Cv2.txt) | 512 B |",
+			wantAssets: []string{"Handbook.pdf", "notes|v2.txt"},
+		},
+		{
+			name:       "attachments matching patterns",
+			html:       `<ac:structured-macro ac:name="attachments"><ac:parameter ac:name="patterns">.*pdf, [</ac:parameter></ac:structured-macro>`,
+			want:       "| File | Size |\n|---|---|\n| [Handbook.pdf](assets%2FHandbook.pdf) | 2.4 MB |",
+			wantAssets: []string{"Handbook.pdf"},
+		},
+		{
+			name:       "multimedia link",
+			html:       `<ac:structured-macro ac:name="multimedia"><ac:parameter ac:name="name"><ri:attachment ri:filename="Demo.mp4" /></ac:parameter></ac:structured-macro>`,
+			want:       "[Demo.mp4](assets%2FDemo.mp4)",
+			wantAssets: []string{"Demo.mp4"},
+		},
+		{
+			name:       "multimedia embed",
+			html:       `<ac:structured-macro ac:name="multimedia"><ac:parameter ac:name="name"><ri:attachment ri:filename="Demo.mp4" /></ac:parameter><ac:parameter ac:name="width">480px</ac:parameter><ac:parameter ac:name="autostart">true</ac:parameter></ac:structured-macro>`,
+			embeds:     true,
+			want:       `<video src="assets%2FDemo.mp4" controls width="480" autoplay></video>`,
+			wantAssets: []string{"Demo.mp4"},
+		},
+		{
+			name: "widget link",
+			html: `<ac:structured-macro ac:name="widget"><ac:parameter ac:name="url"><ri:url ri:value="https://www.youtube.com/watch?v=dQw4w9WgXcQ" /></ac:parameter></ac:structured-macro>`,
+			want: "[https://www.youtube.com/watch?v=dQw4w9WgXcQ](https://www.youtube.com/watch?v=dQw4w9WgXcQ)",
+		},
+		{
+			name:   "youtube embed",
+			html:   `<ac:structured-macro ac:name="widget"><ac:parameter ac:name="url"><ri:url ri:value="https://youtu.be/dQw4w9WgXcQ" /></ac:parameter></ac:structured-macro>`,
+			embeds: true,
+			want:   `<iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ" width="640" height="360" frameborder="0" allowfullscreen></iframe>`,
+		},
+		{
+			name:   "loom embed with size",
+			html:   `<ac:structured-macro ac:name="widget"><ac:parameter ac:name="url"><ri:url ri:value="https://www.loom.com/share/0123abcd?sid=1" /></ac:parameter><ac:parameter ac:name="width">800</ac:parameter><ac:parameter ac:name="height">450</ac:parameter></ac:structured-macro>`,
+			embeds: true,
+			want:   `<iframe src="https://www.loom.com/embed/0123abcd" width="800" height="450" frameborder="0" allowfullscreen></iframe>`,
+		},
+		{
+			name:   "widget for another site",
+			html:   `<ac:structured-macro ac:name="widget"><ac:parameter ac:name="url"><ri:url ri:value="https://twitter.com/atlassian" /></ac:parameter></ac:structured-macro>`,
+			embeds: true,
+			want:   "[https://twitter.com/atlassian](https://twitter.com/atlassian)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			resolver := mock_attachments.NewMockResolver(ctrl)
+			resolver.EXPECT().DownloadAttachment(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(page *model.ConfluencePage, filename string, revision int) (*model.ConfluenceAttachment, []byte, error) {
+					data, ok := files[filename]
+					if !ok {
+						return nil, nil, fmt.Errorf("attachment %s not found", filename)
+					}
+					return &model.ConfluenceAttachment{Title: filename}, []byte(data), nil
+				}).AnyTimes()
+
+			plugin := NewConfluencePlugin(resolver, "assets")
+			plugin.SetCurrentPage(page)
+			plugin.SetBaseURL("https://example.atlassian.net/")
+			plugin.SetMediaEmbeds(tt.embeds)
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+
+			var assets []string
+			for _, asset := range plugin.Assets() {
+				assets = append(assets, asset.FileName)
+			}
+			if strings.Join(assets, ",") != strings.Join(tt.wantAssets, ",") {
+				t.Fatalf("unexpected assets: %v, want %v", assets, tt.wantAssets)
+			}
+		})
+	}
+}
+
+func TestFormatFileSize(t *testing.T) {
+	tests := map[int64]string{
+		0:             "0 B",
+		1023:          "1023 B",
+		1024:          "1.0 KB",
+		5 << 20:       "5.0 MB",
+		3 << 30:       "3.0 GB",
+		2048 << 30:    "2048.0 GB",
+		1_500_000_000: "1.4 GB",
+	}
+	for size, want := range tests {
+		if got := formatFileSize(size); got != want {
+			t.Errorf("formatFileSize(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
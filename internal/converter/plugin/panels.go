@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/callout"
+	"golang.org/x/net/html"
+)
+
+// handlePanelMacro converts panel macros to blockquotes, with the title on the first line.
+// Colours and border styles have no Markdown equivalent and are dropped.
+func (p *ConfluencePlugin) handlePanelMacro(ctx converter.Context, n *html.Node) string {
+	title := macroParameter(n, "title")
+	content := p.convertNestedHTML(ctx, n)
+	if title == "" && content == "" {
+		return ""
+	}
+	return p.callouts.Render(callout.Callout{Kind: callout.KindPanel, Title: title, Content: content})
+}
+
+// handleADFExtension converts editor extensions stored in storage format. Node
+// types without a Markdown form are rendered from their HTML fallback.
+func (p *ConfluencePlugin) handleADFExtension(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	node := findElement(n, "ac:adf-node")
+	result, ok := p.renderADFNode(ctx, node)
+	if !ok {
+		if fallback := findElement(n, "ac:adf-fallback"); fallback != nil {
+			result = renderChildren(ctx, fallback)
+		} else if node != nil {
+			result = p.convertBody(ctx, findElement(node, "ac:adf-content"))
+		}
+	}
+
+	if result != "" {
+		_, _ = w.WriteString("\n\n" + result + "\n\n")
+	}
+	return converter.RenderSuccess
+}
+
+// handleADFNode converts ADF nodes nested in the content of another node, which
+// have no fallback of their own
+func (p *ConfluencePlugin) handleADFNode(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	result, ok := p.renderADFNode(ctx, n)
+	if !ok {
+		// Attributes hold IDs and settings, so only the content is kept
+		result = p.convertBody(ctx, findElement(n, "ac:adf-content"))
+	}
+
+	if result != "" {
+		_, _ = w.WriteString("\n\n" + result + "\n\n")
+	}
+	return converter.RenderSuccess
+}
+
+// renderADFNode converts the node types with a Markdown form, reporting false for others
+func (p *ConfluencePlugin) renderADFNode(ctx converter.Context, node *html.Node) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	switch attrValue(node, "type") {
+	case "panel":
+		return p.handleADFPanel(ctx, node), true
+	case "decision-list":
+		return p.handleADFDecisionList(ctx, node), true
+	case "expand", "nested-expand":
+		return p.handleADFExpand(ctx, node), true
+	default:
+		return "", false
+	}
+}
+
+// handleADFPanel renders Cloud panels like the callout macros, using the custom emoji of custom panels
+func (p *ConfluencePlugin) handleADFPanel(ctx converter.Context, node *html.Node) string {
+	c := callout.Callout{
+		Kind:    callout.PanelKind(adfAttribute(node, "panel-type")),
+		Content: p.convertBody(ctx, findElement(node, "ac:adf-content")),
+	}
+	if c.Kind == callout.KindPanel {
+		c.Icon = adfAttribute(node, "panel-icon-text")
+		if c.Icon == "" {
+			c.Icon = adfAttribute(node, "panel-icon")
+		}
+		if c.Icon == "" && c.Content == "" {
+			return ""
+		}
+	}
+	return p.callouts.Render(c)
+}
+
+// handleADFDecisionList renders decisions as checklist items, marking decided ones with ✔
+func (p *ConfluencePlugin) handleADFDecisionList(ctx converter.Context, node *html.Node) string {
+	var items []string
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.Data != "ac:adf-node" || attrValue(child, "type") != "decision-item" {
+			continue
+		}
+		text := strings.Join(strings.Fields(renderChildren(ctx, findElement(child, "ac:adf-content"))), " ")
+		if text == "" {
+			continue
+		}
+		if adfAttribute(child, "state") == "DECIDED" {
+			items = append(items, "- [x] ✔ "+text)
+		} else {
+			items = append(items, "- [ ] "+text)
+		}
+	}
+	return strings.Join(items, "\n")
+}
+
+// handleADFExpand renders expands and nested expands like the expand macro
+func (p *ConfluencePlugin) handleADFExpand(ctx converter.Context, node *html.Node) string {
+	content := p.convertBody(ctx, findElement(node, "ac:adf-content"))
+	if content == "" {
+		return ""
+	}
+	return p.callouts.Render(callout.Callout{
+		Kind:    callout.KindExpand,
+		Title:   adfAttribute(node, "title"),
+		Content: content,
+	})
+}
+
+// adfAttribute returns the value of an ac:adf-node's direct ac:adf-attribute child
+func adfAttribute(node *html.Node, key string) string {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:adf-attribute" && attrValue(child, "key") == key {
+			return strings.TrimSpace(textContent(child))
+		}
+	}
+	return ""
+}
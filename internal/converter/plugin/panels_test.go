@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/converter/callout"
+)
+
+func TestPanels(t *testing.T) {
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), NewConfluencePlugin(nil, "assets")))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "titled panel",
+			html: `<ac:structured-macro ac:name="panel"><ac:parameter ac:name="title">Checklist</ac:parameter><ac:parameter ac:name="bgColor">#DEEBFF</ac:parameter><ac:parameter ac:name="borderStyle">dashed</ac:parameter><ac:rich-text-body><p>Back up the <strong>database</strong></p><ul><li>Verify</li></ul></ac:rich-text-body></ac:structured-macro>`,
+			want: "> **Checklist**\n>\n> Back up the **database**\n>\n> - Verify",
+		},
+		{
+			name: "untitled panel",
+			html: `<ac:structured-macro ac:name="panel"><ac:rich-text-body><p>Plain</p></ac:rich-text-body></ac:structured-macro>`,
+			want: "> Plain",
+		},
+		{
+			name: "adf note panel",
+			html: `<ac:adf-extension><ac:adf-node type="panel"><ac:adf-attribute key="panel-type">note</ac:adf-attribute><ac:adf-content><p>Remember this</p></ac:adf-content></ac:adf-node><ac:adf-fallback><div class="panel"><p>Remember this</p></div></ac:adf-fallback></ac:adf-extension>`,
+			want: "> 📝 **Note:** Remember this",
+		},
+		{
+			name: "adf success and error panels",
+			html: `<ac:adf-extension><ac:adf-node type="panel"><ac:adf-attribute key="panel-type">success</ac:adf-attribute><ac:adf-content><p>Deployed</p></ac:adf-content></ac:adf-node></ac:adf-extension><ac:adf-extension><ac:adf-node type="panel"><ac:adf-attribute key="panel-type">error</ac:adf-attribute><ac:adf-content><p>Failed</p><p>Retry</p></ac:adf-content></ac:adf-node></ac:adf-extension>`,
+			want: "> ✅ **Success:** Deployed\n\n> ❌ **Error:**\n> Failed\n>\n> Retry",
+		},
+		{
+			name: "adf custom emoji panel",
+			html: `<ac:adf-extension><ac:adf-node type="panel"><ac:adf-attribute key="panel-type">custom</ac:adf-attribute><ac:adf-attribute key="panel-icon">:rocket:</ac:adf-attribute><ac:adf-attribute key="panel-icon-text">🚀</ac:adf-attribute><ac:adf-attribute key="panel-color">#E3FCEF</ac:adf-attribute><ac:adf-content><p>Launch day</p></ac:adf-content></ac:adf-node></ac:adf-extension>`,
+			want: "> 🚀 Launch day",
+		},
+		{
+			name: "unknown extension uses fallback",
+			html: `<ac:adf-extension><ac:adf-node type="unknown-node"><ac:adf-content><p>ignored</p></ac:adf-content></ac:adf-node><ac:adf-fallback><p>Fallback text</p></ac:adf-fallback></ac:adf-extension>`,
+			want: "Fallback text",
+		},
+		{
+			name: "unknown extension without fallback keeps content",
+			html: `<ac:adf-extension><ac:adf-node type="unknown-node"><ac:adf-attribute key="local-id">abc-123</ac:adf-attribute><ac:adf-content><p>Body</p></ac:adf-content></ac:adf-node></ac:adf-extension>`,
+			want: "Body",
+		},
+		{
+			name: "adf decision list",
+			html: `<ac:adf-extension><ac:adf-node type="decision-list"><ac:adf-attribute key="local-id">list-1</ac:adf-attribute>` +
+				`<ac:adf-node type="decision-item"><ac:adf-attribute key="local-id">d1</ac:adf-attribute><ac:adf-attribute key="state">DECIDED</ac:adf-attribute><ac:adf-content>Ship on <strong>Friday</strong></ac:adf-content></ac:adf-node>` +
+				`<ac:adf-node type="decision-item"><ac:adf-attribute key="local-id">d2</ac:adf-attribute><ac:adf-attribute key="state">UNDECIDED</ac:adf-attribute><ac:adf-content>Pick a name</ac:adf-content></ac:adf-node>` +
+				`<ac:adf-node type="decision-item"><ac:adf-attribute key="state">DECIDED</ac:adf-attribute><ac:adf-content></ac:adf-content></ac:adf-node>` +
+				`</ac:adf-node><ac:adf-fallback><ul class="decision-list"><li>Ship on Friday</li><li>Pick a name</li></ul></ac:adf-fallback></ac:adf-extension>`,
+			want: "- [x] ✔ Ship on **Friday**\n- [ ] Pick a name",
+		},
+		{
+			name: "adf expand with nested expand",
+			html: `<ac:adf-extension><ac:adf-node type="expand"><ac:adf-attribute key="title">Outer</ac:adf-attribute><ac:adf-content><p>first</p>` +
+				`<ac:adf-node type="nested-expand"><ac:adf-attribute key="title">Inner</ac:adf-attribute><ac:adf-content><p>second</p></ac:adf-content></ac:adf-node>` +
+				`</ac:adf-content></ac:adf-node><ac:adf-fallback><div class="expand"><p>first</p><p>second</p></div></ac:adf-fallback></ac:adf-extension>`,
+			want: "first\n\nsecond",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalloutStyle(t *testing.T) {
+	plugin := NewConfluencePlugin(nil, "assets")
+	plugin.SetCallouts(callout.New(callout.StyleMkDocs, nil))
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "titled info macro",
+			html: `<ac:structured-macro ac:name="info"><ac:parameter ac:name="title">Heads up</ac:parameter><ac:rich-text-body><p>Read this</p></ac:rich-text-body></ac:structured-macro>`,
+			want: "!!! info \"Heads up\"\n\n    Read this",
+		},
+		{
+			name: "adf panel",
+			html: `<ac:adf-extension><ac:adf-node type="panel"><ac:adf-attribute key="panel-type">error</ac:adf-attribute><ac:adf-content><p>Failed</p></ac:adf-content></ac:adf-node></ac:adf-extension>`,
+			want: "!!! danger \"Error\"\n\n    Failed",
+		},
+		{
+			name: "expand",
+			html: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">Click to see logs</ac:parameter><ac:rich-text-body><p>log line</p></ac:rich-text-body></ac:structured-macro>`,
+			want: "??? note \"Click to see logs\"\n\n    log line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandDetails(t *testing.T) {
+	callouts := callout.New(callout.StyleBlockquote, nil)
+	callouts.SetExpandDetails(true)
+	plugin := NewConfluencePlugin(nil, "assets")
+	plugin.SetCallouts(callouts)
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "titled expand",
+			html: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">Logs &amp; traces</ac:parameter><ac:rich-text-body><p>Some <strong>bold</strong> text</p></ac:rich-text-body></ac:structured-macro>`,
+			want: "<details>\n<summary>Logs &amp; traces</summary>\n\nSome **bold** text\n\n</details>",
+		},
+		{
+			name: "nested expand",
+			html: `<ac:structured-macro ac:name="expand"><ac:parameter ac:name="title">Outer</ac:parameter><ac:rich-text-body><p>first</p><ac:structured-macro ac:name="expand"><ac:rich-text-body><p>inner</p></ac:rich-text-body></ac:structured-macro></ac:rich-text-body></ac:structured-macro>`,
+			want: "<details>\n<summary>Outer</summary>\n\nfirst\n\n<details>\n<summary>Click here to expand...</summary>\n\ninner\n\n</details>\n\n</details>",
+		},
+		{
+			name: "expand in list item",
+			html: `<ul><li>item<ac:structured-macro ac:name="expand"><ac:rich-text-body><p>hidden</p></ac:rich-text-body></ac:structured-macro></li></ul>`,
+			want: "- item\n  \n  <details>\n  <summary>Click here to expand...</summary>\n  \n  hidden\n  \n  </details>",
+		},
+		{
+			name: "adf nested expand",
+			html: `<ac:adf-extension><ac:adf-node type="expand"><ac:adf-attribute key="title">Outer</ac:adf-attribute><ac:adf-content><p>first</p>` +
+				`<ac:adf-node type="nested-expand"><ac:adf-attribute key="title">Inner</ac:adf-attribute><ac:adf-content><p>second</p></ac:adf-content></ac:adf-node>` +
+				`</ac:adf-content></ac:adf-node></ac:adf-extension>`,
+			want: "<details>\n<summary>Outer</summary>\n\nfirst\n\n<details>\n<summary>Inner</summary>\n\nsecond\n\n</details>\n\n</details>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	mdmodel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"golang.org/x/net/html"
+)
+
+// task is an item of a Confluence task list
+type task struct {
+	done     bool
+	content  []*html.Node // Body nodes, without nested task lists
+	subtasks []task
+}
+
+// parseTaskList reads the tasks of an ac:task-list. Nested lists may sit in a
+// task's body or follow the task they belong to.
+func parseTaskList(list *html.Node) []task {
+	var tasks []task
+	for child := list.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch child.Data {
+		case "ac:task":
+			tasks = append(tasks, parseTask(child))
+		case "ac:task-list":
+			if len(tasks) == 0 {
+				tasks = append(tasks, parseTaskList(child)...)
+				continue
+			}
+			last := &tasks[len(tasks)-1]
+			last.subtasks = append(last.subtasks, parseTaskList(child)...)
+		}
+	}
+	return tasks
+}
+
+func parseTask(n *html.Node) task {
+	var t task
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch child.Data {
+		case "ac:task-status":
+			t.done = strings.TrimSpace(textContent(child)) == "complete"
+		case "ac:task-body":
+			for node := child.FirstChild; node != nil; node = node.NextSibling {
+				if node.Type == html.ElementNode && node.Data == "ac:task-list" {
+					t.subtasks = append(t.subtasks, parseTaskList(node)...)
+					continue
+				}
+				t.content = append(t.content, node)
+			}
+		case "ac:task-list":
+			t.subtasks = append(t.subtasks, parseTaskList(child)...)
+		}
+	}
+	return t
+}
+
+// handleTaskList renders a task list as a GitHub task list. Mentions and due
+// dates in task bodies are rendered by their own handlers.
+func (p *ConfluencePlugin) handleTaskList(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	var buf strings.Builder
+	writeTasks(ctx, &buf, parseTaskList(n), "")
+	if buf.Len() == 0 {
+		return converter.RenderSuccess
+	}
+	_, _ = w.WriteString("\n\n" + strings.TrimSuffix(buf.String(), "\n") + "\n\n")
+	return converter.RenderSuccess
+}
+
+// writeTasks writes tasks as GitHub task list items, skipping empty ones
+func writeTasks(ctx converter.Context, w *strings.Builder, tasks []task, indent string) {
+	for _, t := range tasks {
+		var body strings.Builder
+		for _, node := range t.content {
+			ctx.RenderNodes(ctx, &body, node)
+		}
+		text := strings.Join(strings.Fields(body.String()), " ")
+		// Meeting notes leave an empty task at the end of the list
+		if text == "" && len(t.subtasks) == 0 {
+			continue
+		}
+
+		w.WriteString(indent + "- " + taskCheckbox(t.done, "[x]", "[ ]"))
+		if text != "" {
+			w.WriteString(" " + text)
+		}
+		w.WriteString("\n")
+		writeTasks(ctx, w, t.subtasks, indent+"  ")
+	}
+}
+
+// flattenTaskList writes a task list on separate lines of a table cell, where
+// Markdown task lists cannot be used
+func (p *ConfluencePlugin) flattenTaskList(ctx converter.Context, w *strings.Builder, taskListNode *html.Node) {
+	w.WriteString("<br>")
+	p.flattenTasks(ctx, w, parseTaskList(taskListNode), "")
+}
+
+func (p *ConfluencePlugin) flattenTasks(ctx converter.Context, w *strings.Builder, tasks []task, indent string) {
+	for _, t := range tasks {
+		var body strings.Builder
+		for _, node := range t.content {
+			p.flattenCellNode(ctx, &body, node)
+		}
+		text := strings.Join(strings.Fields(body.String()), " ")
+		if text == "" && len(t.subtasks) == 0 {
+			continue
+		}
+
+		w.WriteString(indent + taskCheckbox(t.done, "☑", "☐"))
+		if text != "" {
+			w.WriteString(" " + text)
+		}
+		w.WriteString("<br>")
+		p.flattenTasks(ctx, w, t.subtasks, indent+"&emsp;")
+	}
+}
+
+func taskCheckbox(done bool, checked, unchecked string) string {
+	if done {
+		return checked
+	}
+	return unchecked
+}
+
+// Tasks returns the tasks written on the current page, nested tasks included
+func (p *ConfluencePlugin) Tasks() []mdmodel.Task {
+	return p.tasks
+}
+
+// collectTasks records every non-empty task beneath doc in document order
+func (p *ConfluencePlugin) collectTasks(doc *html.Node) {
+	p.tasks = nil
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "ac:task" {
+			if summary := p.summarizeTask(parseTask(n)); summary.Text != "" {
+				p.tasks = append(p.tasks, summary)
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+}
+
+// summarizeTask reads a task's plain text. The first mentioned user is its
+// assignee and the first date its due date, as in Confluence's task report.
+func (p *ConfluencePlugin) summarizeTask(t task) mdmodel.Task {
+	summary := mdmodel.Task{Status: taskCheckbox(t.done, "complete", "incomplete")}
+
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			text.WriteString(n.Data)
+			return
+		case n.Type != html.ElementNode:
+			return
+		}
+		switch n.Data {
+		case "ac:task-list":
+			// Tasks swallowed by a self-closing element are collected on their own
+			return
+		case "ri:user":
+			accountID := attrValue(n, "ri:account-id")
+			if accountID == "" {
+				return
+			}
+			name := p.userName(accountID)
+			if summary.Assignee == "" {
+				summary.Assignee = name
+			}
+			text.WriteString(" @" + name + " ")
+		case "time":
+			due := attrValue(n, "datetime")
+			if summary.Due == "" {
+				summary.Due = due
+			}
+			text.WriteString(" " + due + " ")
+		case "p", "br", "li":
+			text.WriteString(" ")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, node := range t.content {
+		walk(node)
+	}
+	summary.Text = strings.Join(strings.Fields(text.String()), " ")
+	return summary
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	mdmodel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+func TestTaskLists(t *testing.T) {
+	plugin := NewConfluencePlugin(nil, "assets")
+	plugin.userCache["557058:jane"] = "Jane Doe"
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	nested := `<ac:task-list>` +
+		`<ac:task><ac:task-id>1</ac:task-id><ac:task-status>incomplete</ac:task-status><ac:task-body>Ship <strong>release</strong> <ac:link><ri:user ri:account-id="557058:jane" /></ac:link> <time datetime="2024-01-02" />` +
+		`<ac:task-list><ac:task><ac:task-id>2</ac:task-id><ac:task-status>complete</ac:task-status><ac:task-body>Tag build</ac:task-body></ac:task></ac:task-list>` +
+		`</ac:task-body></ac:task>` +
+		`<ac:task><ac:task-id>3</ac:task-id><ac:task-status>complete</ac:task-status><ac:task-body>Write notes</ac:task-body></ac:task>` +
+		`<ac:task><ac:task-id>4</ac:task-id><ac:task-status>incomplete</ac:task-status><ac:task-body /></ac:task>` +
+		`</ac:task-list>`
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "nested tasks with assignee and due date",
+			html: `<p>Action items</p>` + nested + `<p>Next</p>`,
+			want: "Action items\n\n- [ ] Ship **release** @Jane Doe 2024-01-02\n  - [x] Tag build\n- [x] Write notes\n\nNext",
+		},
+		{
+			name: "nested list following its task",
+			html: `<ac:task-list><ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body>Parent</ac:task-body></ac:task>` +
+				`<ac:task-list><ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body>Child</ac:task-body></ac:task></ac:task-list></ac:task-list>`,
+			want: "- [ ] Parent\n  - [ ] Child",
+		},
+		{
+			name: "unknown assignee",
+			html: `<ac:task-list><ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body>Review <ac:link><ri:user ri:account-id="557058:bob" /></ac:link></ac:task-body></ac:task></ac:task-list>`,
+			want: "- [ ] Review @user(557058:bob)",
+		},
+		{
+			name: "only empty tasks",
+			html: `<p>Before</p><ac:task-list><ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body /></ac:task></ac:task-list>`,
+			want: "Before",
+		},
+		{
+			name: "table cell",
+			html: `<table><tbody><tr><th>Owner</th><th>Tasks</th></tr><tr><td>Ops</td><td>` + nested + `</td></tr></tbody></table>`,
+			want: "| Owner | Tasks |\n|---|---|\n| Ops | <br>☐ Ship <strong>release</strong> @Jane Doe 2024-01-02<br>&emsp;☑ Tag build<br>☑ Write notes<br> |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectTasks(t *testing.T) {
+	plugin := NewConfluencePlugin(nil, "assets")
+	plugin.userCache["557058:jane"] = "Jane Doe"
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+	html := `<ac:task-list>` +
+		`<ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body>Ship <ac:link><ri:user ri:account-id="557058:jane" /></ac:link> by <time datetime="2024-01-02" />` +
+		`<ac:task-list><ac:task><ac:task-status>complete</ac:task-status><ac:task-body>Tag build</ac:task-body></ac:task></ac:task-list>` +
+		`</ac:task-body></ac:task>` +
+		`<ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body /></ac:task>` +
+		`</ac:task-list>`
+	if _, err := conv.ConvertString(html); err != nil {
+		t.Fatalf("convert error: %v", err)
+	}
+
+	want := []mdmodel.Task{
+		{Status: "incomplete", Text: "Ship @Jane Doe by 2024-01-02", Assignee: "Jane Doe", Due: "2024-01-02"},
+		{Status: "complete", Text: "Tag build"},
+	}
+	if got := plugin.Tasks(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tasks:\n got: %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// DefaultTextDiagrams maps the macros of diagram-as-code apps to the code fence
+// language their source is written out with
+var DefaultTextDiagrams = map[string]string{
+	"mermaid-cloud":  "mermaid",
+	"mermaid":        "mermaid",
+	"mermaid-macro":  "mermaid",
+	"plantuml":       "plantuml",
+	"plantumlrender": "plantuml",
+	"graphviz":       "dot",
+}
+
+// SetTextDiagrams adds or overrides macro name to code fence language mappings.
+// An empty language removes the macro's mapping.
+func (p *ConfluencePlugin) SetTextDiagrams(languages map[string]string) {
+	if p.textDiagrams == nil {
+		p.textDiagrams = make(map[string]string, len(languages))
+	}
+	for macro, language := range languages {
+		if language == "" {
+			delete(p.textDiagrams, macro)
+			continue
+		}
+		p.textDiagrams[macro] = language
+	}
+}
+
+// handleTextDiagramMacro writes a diagram's source as a fenced code block. The
+// source is the macro body or, for apps that store it separately, an attachment.
+func (p *ConfluencePlugin) handleTextDiagramMacro(n *html.Node, language string) string {
+	var buf strings.Builder
+	_ = html.Render(&buf, n)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
+	if err != nil {
+		return fmt.Sprintf("<!-- Error rendering macro: %s -->", err.Error())
+	}
+	selection := doc.Selection
+	label := strings.ToUpper(language[:1]) + language[1:]
+
+	diagram := ""
+	if selection.Find("ac\\:plain-text-body").Length() > 0 {
+		rawHTML, _ := selection.Html()
+		diagram = extractPlainTextBodyContent(selection, rawHTML)
+	} else {
+		filename := extractMacroParameter(selection, "filename")
+		if filename == "" {
+			filename = extractMacroParameter(selection, "attachment")
+		}
+		revision := 0
+		if parsed, err := strconv.Atoi(strings.TrimSpace(extractMacroParameter(selection, "revision"))); err == nil {
+			revision = parsed
+		}
+
+		if filename == "" {
+			return fmt.Sprintf("<!-- %s macro missing filename -->", label)
+		}
+		if p.attachmentResolver == nil || p.currentPage == nil {
+			return fmt.Sprintf("<!-- %s attachment %s unavailable -->", label, filename)
+		}
+		diagram, err = p.attachmentResolver.Resolve(p.currentPage, filename, revision)
+		if err != nil {
+			return fmt.Sprintf("<!-- Failed to load %s %s: %v -->", language, filename, err)
+		}
+	}
+
+	diagram = strings.TrimSpace(diagram)
+	if diagram == "" {
+		return fmt.Sprintf("<!-- Empty %s macro -->", language)
+	}
+	return fmt.Sprintf("```%s\n%s\n```\n", language, diagram)
+}
@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	mock_attachments "github.com/jackchuka/confluence-md/internal/converter/plugin/attachments/mock"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestTextDiagramMacros(t *testing.T) {
+	files := map[string]string{
+		"flow": "graph TD;\nA-->B;",
+	}
+
+	tests := []struct {
+		name     string
+		html     string
+		diagrams map[string]string
+		want     string
+	}{
+		{
+			name: "plantuml body in CDATA",
+			html: `<ac:structured-macro ac:name="plantuml"><ac:plain-text-body><pre data-cdata='true'>@startuml
+Alice -&gt; Bob: "hi" &lt;b&gt; &amp; bye
+@enduml</pre></ac:plain-text-body></ac:structured-macro>`,
+			want: "```plantuml\n@startuml\nAlice -> Bob: \"hi\" <b> & bye\n@enduml\n```",
+		},
+		{
+			name: "graphviz body",
+			html: `<ac:structured-macro ac:name="graphviz"><ac:plain-text-body><pre data-cdata='true'>digraph { a -&gt; b }</pre></ac:plain-text-body></ac:structured-macro>`,
+			want: "```dot\ndigraph { a -> b }\n```",
+		},
+		{
+			name: "mermaid body",
+			html: `<ac:structured-macro ac:name="mermaid-macro"><ac:plain-text-body><pre data-cdata='true'>sequenceDiagram
+A-&gt;&gt;B: hello</pre></ac:plain-text-body></ac:structured-macro>`,
+			want: "```mermaid\nsequenceDiagram\nA->>B: hello\n```",
+		},
+		{
+			name: "mermaid attachment",
+			html: `<ac:structured-macro ac:name="mermaid-cloud"><ac:parameter ac:name="filename">flow</ac:parameter><ac:parameter ac:name="revision">2</ac:parameter></ac:structured-macro>`,
+			want: "```mermaid\ngraph TD;\nA-->B;\n```",
+		},
+		{
+			name: "attachment that cannot be loaded",
+			html: `<ac:structured-macro ac:name="mermaid-cloud"><ac:parameter ac:name="filename">gone</ac:parameter></ac:structured-macro>`,
+			want: "<!-- Failed to load mermaid gone: attachment gone not found -->",
+		},
+		{
+			name: "missing filename",
+			html: `<ac:structured-macro ac:name="mermaid-cloud"></ac:structured-macro>`,
+			want: "<!-- Mermaid macro missing filename -->",
+		},
+		{
+			name: "empty body",
+			html: `<ac:structured-macro ac:name="plantuml"><ac:plain-text-body><pre data-cdata='true'>  </pre></ac:plain-text-body></ac:structured-macro>`,
+			want: "<!-- Empty plantuml macro -->",
+		},
+		{
+			name: "unknown macro falls back to unsupported",
+			html: `<ac:structured-macro ac:name="kroki-ditaa"><ac:plain-text-body><pre data-cdata='true'>+--+</pre></ac:plain-text-body></ac:structured-macro>`,
+			want: "<!-- Unsupported macro: kroki-ditaa -->",
+		},
+		{
+			name:     "added macro",
+			html:     `<ac:structured-macro ac:name="kroki-ditaa"><ac:plain-text-body><pre data-cdata='true'>+--+</pre></ac:plain-text-body></ac:structured-macro>`,
+			diagrams: map[string]string{"kroki-ditaa": "ditaa"},
+			want:     "```ditaa\n+--+\n```",
+		},
+		{
+			name:     "removed default",
+			html:     `<ac:structured-macro ac:name="graphviz"><ac:plain-text-body><pre data-cdata='true'>digraph { a -&gt; b }</pre></ac:plain-text-body></ac:structured-macro>`,
+			diagrams: map[string]string{"graphviz": ""},
+			want:     "<!-- Unsupported macro: graphviz -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			resolver := mock_attachments.NewMockResolver(ctrl)
+			resolver.EXPECT().Resolve(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(page *model.ConfluencePage, filename string, revision int) (string, error) {
+					data, ok := files[filename]
+					if !ok {
+						return "", errors.New("attachment " + filename + " not found")
+					}
+					return data, nil
+				}).AnyTimes()
+
+			plugin := NewConfluencePlugin(resolver, "assets")
+			plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Home", SpaceKey: "DOCS"})
+			plugin.SetTextDiagrams(tt.diagrams)
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextDiagramMacroCDATAComment(t *testing.T) {
+	// goquery reads an unprocessed CDATA section as a comment
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<ac:structured-macro ac:name="plantuml"><ac:plain-text-body><!--[CDATA[@startuml
+A -> B: <b>
+@enduml]]></ac:plain-text-body></ac:structured-macro>`, "ac:structured-macro")
+	if got := plugin.handleTextDiagramMacro(node, "plantuml"); got != "```plantuml\n@startuml\nA -> B: <b>\n@enduml\n```\n" {
+		t.Fatalf("unexpected diagram block: %q", got)
+	}
+}
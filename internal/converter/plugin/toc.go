@@ -0,0 +1,256 @@
+package plugin
+
+import (
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// tocEntry is a heading listed in a table of contents
+type tocEntry struct {
+	level int
+	text  string
+	id    string
+}
+
+// tocOptions holds the toc macro parameters that shape the generated list
+type tocOptions struct {
+	minLevel  int
+	maxLevel  int
+	include   *regexp.Regexp
+	exclude   *regexp.Regexp
+	flat      bool
+	ordered   bool
+	separator string
+}
+
+// orderedListStyles are the CSS list styles Confluence numbers
+var orderedListStyles = map[string]bool{
+	"decimal": true, "decimal-leading-zero": true,
+	"lower-alpha": true, "upper-alpha": true, "lower-latin": true, "upper-latin": true,
+	"lower-roman": true, "upper-roman": true, "lower-greek": true,
+}
+
+// flatSeparators maps the named separators of flat tables of contents to the text
+// written before, between and after entries
+var flatSeparators = map[string][3]string{
+	"brackets": {"[ ", " ] [ ", " ]"},
+	"braces":   {"{ ", " } { ", " }"},
+	"parens":   {"( ", " ) ( ", " )"},
+	"pipe":     {"", " | ", ""},
+}
+
+// SetTOCPlaceholder writes toc macros as a placeholder comment instead of a generated
+// list, for renderers that build their own table of contents
+func (p *ConfluencePlugin) SetTOCPlaceholder(enabled bool) {
+	p.tocPlaceholder = enabled
+}
+
+// buildTOCs replaces toc macros with a list of links to the page's headings
+func (p *ConfluencePlugin) buildTOCs(ctx converter.Context, doc *html.Node) {
+	if p.tocPlaceholder {
+		return
+	}
+
+	var macros []*html.Node
+	var entries []tocEntry
+	slugger := NewSlugger(p.headingIDs, p.currentTitle())
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case isTOCMacro(n):
+				macros = append(macros, n)
+				return
+			case isHeading(n):
+				if text := strings.TrimSpace(textContent(n)); text != "" {
+					level, _ := strconv.Atoi(n.Data[1:])
+					entries = append(entries, tocEntry{level: level, text: text, id: slugger.HeadingID(text)})
+				}
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	for _, macro := range macros {
+		if macro.Parent == nil {
+			continue
+		}
+		opts := parseTOCOptions(macro)
+		if toc := opts.render(opts.filter(entries)); toc != nil {
+			macro.Parent.InsertBefore(toc, macro)
+		}
+		macro.Parent.RemoveChild(macro)
+	}
+}
+
+func isTOCMacro(n *html.Node) bool {
+	if n.Data == "ac:structured-macro" {
+		return attrValue(n, "ac:name") == "toc"
+	}
+	return n.Data == "div" && hasClass(n, "toc-macro")
+}
+
+// parseTOCOptions reads the parameters of a storage format macro, or the heading
+// levels of a view format one
+func parseTOCOptions(n *html.Node) tocOptions {
+	opts := tocOptions{minLevel: 1, maxLevel: 6, separator: "brackets"}
+
+	if n.Data == "div" {
+		// View format lists the included heading levels, e.g. data-headerelements="H1,H2,H3"
+		if elements := attrValue(n, "data-headerelements"); elements != "" {
+			opts.minLevel, opts.maxLevel = 6, 1
+			for _, element := range strings.Split(elements, ",") {
+				level, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(element)), "H"))
+				if err != nil || level < 1 || level > 6 {
+					continue
+				}
+				opts.minLevel = min(opts.minLevel, level)
+				opts.maxLevel = max(opts.maxLevel, level)
+			}
+		}
+		return opts
+	}
+
+	if level, err := strconv.Atoi(macroParameter(n, "minLevel")); err == nil {
+		opts.minLevel = max(level, 1)
+	}
+	if level, err := strconv.Atoi(macroParameter(n, "maxLevel")); err == nil {
+		opts.maxLevel = min(level, 6)
+	}
+	opts.include = compileTOCFilter(macroParameter(n, "include"))
+	opts.exclude = compileTOCFilter(macroParameter(n, "exclude"))
+	opts.flat = strings.EqualFold(macroParameter(n, "type"), "flat")
+	opts.ordered = orderedListStyles[strings.ToLower(macroParameter(n, "style"))] ||
+		strings.EqualFold(macroParameter(n, "outline"), "true")
+	if separator := macroParameter(n, "separator"); separator != "" {
+		opts.separator = separator
+	}
+	return opts
+}
+
+// compileTOCFilter compiles an include or exclude pattern, which like Confluence's
+// must match the whole heading
+func compileTOCFilter(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		log.Printf("Ignoring invalid toc pattern %q: %v", pattern, err)
+		return nil
+	}
+	return re
+}
+
+// filter returns the entries within the level range that pass the include and exclude patterns
+func (o tocOptions) filter(entries []tocEntry) []tocEntry {
+	var filtered []tocEntry
+	for _, entry := range entries {
+		if entry.level < o.minLevel || entry.level > o.maxLevel {
+			continue
+		}
+		if o.include != nil && !o.include.MatchString(entry.text) {
+			continue
+		}
+		if o.exclude != nil && o.exclude.MatchString(entry.text) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// render builds the HTML the converter turns into the Markdown table of contents
+func (o tocOptions) render(entries []tocEntry) *html.Node {
+	if len(entries) == 0 {
+		return nil
+	}
+	if o.flat {
+		return o.renderFlat(entries)
+	}
+	return o.renderList(entries)
+}
+
+// renderList nests entries by heading level, starting from the first entry's level
+func (o tocOptions) renderList(entries []tocEntry) *html.Node {
+	listTag := "ul"
+	if o.ordered {
+		listTag = "ol"
+	}
+
+	type openList struct {
+		list  *html.Node
+		level int
+	}
+	root := newElement(listTag)
+	stack := []openList{{list: root, level: entries[0].level}}
+
+	for _, entry := range entries {
+		for len(stack) > 1 && entry.level < stack[len(stack)-1].level {
+			stack = stack[:len(stack)-1]
+		}
+		if top := stack[len(stack)-1]; entry.level > top.level {
+			parent := top.list.LastChild
+			if parent == nil {
+				parent = newElement("li")
+				top.list.AppendChild(parent)
+			}
+			nested := newElement(listTag)
+			parent.AppendChild(nested)
+			stack = append(stack, openList{list: nested, level: entry.level})
+		}
+
+		item := newElement("li")
+		item.AppendChild(tocLink(entry))
+		stack[len(stack)-1].list.AppendChild(item)
+	}
+	return root
+}
+
+// renderFlat writes entries on one line, framed by the macro's separator
+func (o tocOptions) renderFlat(entries []tocEntry) *html.Node {
+	separator, ok := flatSeparators[o.separator]
+	if !ok {
+		separator = [3]string{"", " " + strings.TrimSpace(o.separator) + " ", ""}
+	}
+
+	paragraph := newElement("p")
+	for i, entry := range entries {
+		text := separator[1]
+		if i == 0 {
+			text = separator[0]
+		}
+		if text != "" {
+			paragraph.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+		}
+		paragraph.AppendChild(tocLink(entry))
+	}
+	if separator[2] != "" {
+		paragraph.AppendChild(&html.Node{Type: html.TextNode, Data: separator[2]})
+	}
+	return paragraph
+}
+
+func tocLink(entry tocEntry) *html.Node {
+	link := &html.Node{
+		Type: html.ElementNode,
+		Data: "a",
+		Attr: []html.Attribute{{Key: "href", Val: "#" + entry.id}},
+	}
+	link.AppendChild(&html.Node{Type: html.TextNode, Data: entry.text})
+	return link
+}
+
+func newElement(tag string) *html.Node {
+	return &html.Node{Type: html.ElementNode, Data: tag}
+}
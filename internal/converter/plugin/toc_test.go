@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestTOC(t *testing.T) {
+	tests := []struct {
+		name        string
+		style       HeadingIDStyle
+		placeholder bool
+		html        string
+		want        string
+	}{
+		{
+			name:  "nested list with confluence ids",
+			style: HeadingIDsConfluence,
+			html:  `<ac:structured-macro ac:name="toc" /><h1>Intro</h1><h2>Setup</h2><h1>Intro</h1>`,
+			want:  "- [Intro](#ReleaseNotes-Intro)\n  \n  - [Setup](#ReleaseNotes-Setup)\n- [Intro](#ReleaseNotes-Intro.1)\n\n# <a id=\"ReleaseNotes-Intro\"></a>Intro\n\n## <a id=\"ReleaseNotes-Setup\"></a>Setup\n\n# <a id=\"ReleaseNotes-Intro.1\"></a>Intro",
+		},
+		{
+			name:  "level range and decimal style",
+			style: HeadingIDsGitHub,
+			html:  `<ac:structured-macro ac:name="toc"><ac:parameter ac:name="minLevel">2</ac:parameter><ac:parameter ac:name="maxLevel">2</ac:parameter><ac:parameter ac:name="style">decimal</ac:parameter></ac:structured-macro><h1>Title</h1><h2>Rollback Plan</h2><h3>Detail</h3><h2>Contacts</h2>`,
+			want:  "1. [Rollback Plan](#rollback-plan)\n2. [Contacts](#contacts)\n\n# Title\n\n## Rollback Plan\n\n### Detail\n\n## Contacts",
+		},
+		{
+			name:  "include and exclude patterns",
+			style: HeadingIDsGitHub,
+			html:  `<ac:structured-macro ac:name="toc"><ac:parameter ac:name="include">Step.*</ac:parameter><ac:parameter ac:name="exclude">.*Optional.*</ac:parameter></ac:structured-macro><h2>Step 1</h2><h2>Step 2 Optional</h2><h2>Appendix Step</h2>`,
+			want:  "- [Step 1](#step-1)\n\n## Step 1\n\n## Step 2 Optional\n\n## Appendix Step",
+		},
+		{
+			name:  "flat with pipe separator",
+			style: HeadingIDsGitHub,
+			html:  `<ac:structured-macro ac:name="toc"><ac:parameter ac:name="type">flat</ac:parameter><ac:parameter ac:name="separator">pipe</ac:parameter></ac:structured-macro><h2>One</h2><h2>Two</h2>`,
+			want:  "[One](#one) | [Two](#two)\n\n## One\n\n## Two",
+		},
+		{
+			name:  "view format macro",
+			style: HeadingIDsGitHub,
+			html:  `<div class="toc-macro client-side-toc-macro" data-headerelements="H2"></div><h1>Title</h1><h2>Usage</h2>`,
+			want:  "- [Usage](#usage)\n\n# Title\n\n## Usage",
+		},
+		{
+			name:  "no matching headings",
+			style: HeadingIDsGitHub,
+			html:  `<ac:structured-macro ac:name="toc"><ac:parameter ac:name="maxLevel">1</ac:parameter></ac:structured-macro><p>text</p>`,
+			want:  "text",
+		},
+		{
+			name:        "placeholder",
+			style:       HeadingIDsGitHub,
+			placeholder: true,
+			html:        `<ac:structured-macro ac:name="toc" /><h2>Usage</h2>`,
+			want:        "<!-- Table of Contents -->\n\n## Usage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewConfluencePlugin(nil, "assets")
+			plugin.SetHeadingIDStyle(tt.style)
+			plugin.SetTOCPlaceholder(tt.placeholder)
+			plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Title: "Release Notes", SpaceKey: "DOCS"})
+			conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), plugin))
+
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package plugin
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/callout"
+	"github.com/jackchuka/confluence-md/internal/converter/layout"
+	mdmodel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"golang.org/x/net/html"
+)
+
+// ViewPlugin converts the rendered view markup found in Confluence HTML space exports
+type ViewPlugin struct {
+	imageFolder string
+	currentPage *model.ConfluencePage
+	referenced  []string
+	callouts    *callout.Renderer
+	codeTitles  CodeTitleStyle
+	layouts     layout.Style
+	tasks       []mdmodel.Task
+}
+
+// informationMacroKinds maps view-format callout classes to the storage macro callout kinds
+var informationMacroKinds = map[string]callout.Kind{
+	"confluence-information-macro-information": callout.KindInfo,
+	"confluence-information-macro-warning":     callout.KindWarning,
+	"confluence-information-macro-note":        callout.KindNote,
+	"confluence-information-macro-tip":         callout.KindTip,
+}
+
+// lozengeColours maps aui-lozenge modifier classes to status macro colours
+var lozengeColours = map[string]string{
+	"aui-lozenge-error":    "red",
+	"aui-lozenge-current":  "yellow",
+	"aui-lozenge-success":  "green",
+	"aui-lozenge-complete": "blue",
+}
+
+var (
+	highlighterParam = regexp.MustCompile(`([\w-]+):\s*([^;]+)`)
+	columnWidthStyle = regexp.MustCompile(`(?:^|;)\s*width:\s*([^;]+)`)
+	exportPageRef    = regexp.MustCompile(`(?:^|_)(\d+)\.html$`)
+)
+
+// NewViewPlugin creates a plugin for Confluence view-format markup
+func NewViewPlugin(imageFolder string) *ViewPlugin {
+	return &ViewPlugin{imageFolder: imageFolder, callouts: callout.New(callout.StyleBlockquote, nil)}
+}
+
+// SetCallouts selects the renderer for callout macros, panels and expands
+func (p *ViewPlugin) SetCallouts(r *callout.Renderer) {
+	p.callouts = r
+}
+
+// SetCurrentPage records which page is currently being converted
+func (p *ViewPlugin) SetCurrentPage(page *model.ConfluencePage) {
+	p.currentPage = page
+	p.referenced = nil
+}
+
+// ReferencedAttachments returns the attachment titles referenced while converting the current page
+func (p *ViewPlugin) ReferencedAttachments() []string {
+	return p.referenced
+}
+
+// Tasks returns the tasks written on the current page, nested tasks included
+func (p *ViewPlugin) Tasks() []mdmodel.Task {
+	return p.tasks
+}
+
+// Name returns the plugin name
+func (p *ViewPlugin) Name() string {
+	return "confluence-view"
+}
+
+// Init initializes the plugin
+func (p *ViewPlugin) Init(conv *converter.Converter) error {
+	conv.Register.RendererFor("div", converter.TagTypeBlock, p.handleDiv, converter.PriorityEarly)
+	conv.Register.RendererFor("span", converter.TagTypeInline, p.handleSpan, converter.PriorityEarly)
+	conv.Register.RendererFor("img", converter.TagTypeInline, p.handleImage, converter.PriorityEarly)
+	conv.Register.RendererFor("a", converter.TagTypeInline, p.handleAnchor, converter.PriorityEarly)
+	conv.Register.RendererFor("ul", converter.TagTypeBlock, p.handleTaskList, converter.PriorityEarly)
+	conv.Register.PreRenderer(p.collectTasks, converter.PriorityStandard)
+
+	return nil
+}
+
+// SetCodeTitleStyle selects how code block titles are written
+func (p *ViewPlugin) SetCodeTitleStyle(style CodeTitleStyle) {
+	p.codeTitles = style
+}
+
+// SetLayoutStyle selects how the columns of page layouts and section macros are written
+func (p *ViewPlugin) SetLayoutStyle(style layout.Style) {
+	p.layouts = style
+}
+
+// handleDiv converts view-format macro containers
+func (p *ViewPlugin) handleDiv(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	var result string
+	switch {
+	case hasClass(n, "confluence-information-macro"):
+		result = p.handleInformationMacro(ctx, n)
+	case hasClass(n, "code") && hasClass(n, "panel"), hasClass(n, "preformatted") && hasClass(n, "panel"):
+		result = p.handleCodePanel(n)
+	case hasClass(n, "panel") && findByClass(n, "panelContent") != nil:
+		result = p.handlePanel(ctx, n)
+	case hasClass(n, "expand-container"):
+		result = p.handleExpand(ctx, n)
+	case hasClass(n, "toc-macro"):
+		result = "<!-- Table of Contents -->"
+	case hasClass(n, "columnLayout"):
+		result = p.handleColumnLayout(ctx, n)
+	case hasClass(n, "sectionMacroRow"):
+		result = p.handleSectionRow(ctx, n)
+	default:
+		return converter.RenderTryNext
+	}
+
+	if result != "" {
+		_, _ = w.WriteString("\n\n" + result + "\n\n")
+	}
+	return converter.RenderSuccess
+}
+
+func (p *ViewPlugin) handleInformationMacro(ctx converter.Context, n *html.Node) string {
+	kind := callout.KindInfo
+	for class, k := range informationMacroKinds {
+		if hasClass(n, class) {
+			kind = k
+			break
+		}
+	}
+
+	return p.callouts.Render(callout.Callout{
+		Kind:    kind,
+		Title:   strings.TrimSpace(textContent(findByClass(n, "title"))),
+		Content: renderChildren(ctx, findByClass(n, "confluence-information-macro-body")),
+	})
+}
+
+// handlePanel converts panel macros to blockquotes, matching the storage format output
+func (p *ViewPlugin) handlePanel(ctx converter.Context, n *html.Node) string {
+	title := strings.TrimSpace(textContent(findByClass(n, "panelHeader")))
+	content := renderChildren(ctx, findByClass(n, "panelContent"))
+	if title == "" && content == "" {
+		return ""
+	}
+	return p.callouts.Render(callout.Callout{Kind: callout.KindPanel, Title: title, Content: content})
+}
+
+// handleExpand converts expand macros, keeping the title for styles that show it
+func (p *ViewPlugin) handleExpand(ctx converter.Context, n *html.Node) string {
+	content := renderChildren(ctx, findByClass(n, "expand-content"))
+	if content == "" {
+		return ""
+	}
+	return p.callouts.Render(callout.Callout{
+		Kind:    callout.KindExpand,
+		Title:   strings.TrimSpace(textContent(findByClass(n, "expand-control-text"))),
+		Content: content,
+	})
+}
+
+// handleColumnLayout converts a page layout section, whose cells are columns
+func (p *ViewPlugin) handleColumnLayout(ctx converter.Context, n *html.Node) string {
+	var cells []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && hasClass(child, "cell") {
+			cells = append(cells, child)
+		}
+	}
+
+	widths := layout.SectionWidths(attrValue(n, "data-layout"), len(cells))
+	columns := make([]layout.Column, len(cells))
+	for i, cell := range cells {
+		if inner := findByClass(cell, "innerCell"); inner != nil {
+			cell = inner
+		}
+		columns[i].Content = renderChildren(ctx, cell)
+		if widths != nil {
+			columns[i].Width = widths[i]
+		}
+	}
+	return layout.Render(p.layouts, columns)
+}
+
+// handleSectionRow converts the column macros of a section macro
+func (p *ViewPlugin) handleSectionRow(ctx converter.Context, n *html.Node) string {
+	var columns []layout.Column
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || !hasClass(child, "columnMacro") {
+			continue
+		}
+		column := layout.Column{Content: renderChildren(ctx, child)}
+		if match := columnWidthStyle.FindStringSubmatch(attrValue(child, "style")); match != nil {
+			column.Width = strings.TrimSpace(match[1])
+		}
+		columns = append(columns, column)
+	}
+	return layout.Render(p.layouts, columns)
+}
+
+// handleCodePanel converts syntax-highlighted and preformatted panels to fenced code blocks
+func (p *ViewPlugin) handleCodePanel(n *html.Node) string {
+	pre := findElement(n, "pre")
+	if pre == nil {
+		return ""
+	}
+
+	// Code macro parameters are kept as e.g. "brush: java; gutter: true; first-line: 5"
+	params := make(map[string]string)
+	for _, match := range highlighterParam.FindAllStringSubmatch(attrValue(pre, "data-syntaxhighlighter-params"), -1) {
+		params[match[1]] = strings.TrimSpace(match[2])
+	}
+	firstLine, _ := strconv.Atoi(params["first-line"])
+
+	block := codeBlock{
+		language:    params["brush"],
+		title:       strings.TrimSpace(textContent(findByClass(n, "codeHeader"))),
+		lineNumbers: params["gutter"] == "true",
+		firstLine:   firstLine,
+		collapse:    params["collapse"] == "true",
+		code:        strings.TrimRight(textContent(pre), "\n"),
+	}
+	return strings.TrimRight(block.format(p.codeTitles), "\n")
+}
+
+// handleSpan converts status lozenges
+func (p *ViewPlugin) handleSpan(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if !hasClass(n, "status-macro") {
+		return converter.RenderTryNext
+	}
+
+	colour := "grey"
+	for class, c := range lozengeColours {
+		if hasClass(n, class) {
+			colour = c
+			break
+		}
+	}
+
+	_, _ = w.WriteString(formatStatus(strings.TrimSpace(textContent(n)), colour))
+	return converter.RenderSuccess
+}
+
+// handleImage converts embedded attachment images and emoticons
+func (p *ViewPlugin) handleImage(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if hasClass(n, "emoticon") {
+		if fallback := attrValue(n, "data-emoji-fallback"); fallback != "" {
+			_, _ = w.WriteString(fallback)
+		} else {
+			_, _ = w.WriteString(attrValue(n, "alt"))
+		}
+		return converter.RenderSuccess
+	}
+
+	title, ok := p.attachmentTitle(attrValue(n, "src"))
+	if !ok {
+		return converter.RenderTryNext
+	}
+
+	_, _ = fmt.Fprintf(w, "![%s](%s)", title, p.localPath(title))
+	return converter.RenderSuccess
+}
+
+// handleAnchor converts attachment, user and page links
+func (p *ViewPlugin) handleAnchor(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	href := attrValue(n, "href")
+	text := strings.TrimSpace(textContent(n))
+
+	if hasClass(n, "confluence-userlink") || attrValue(n, "data-username") != "" {
+		_, _ = fmt.Fprintf(w, " @%s ", text)
+		return converter.RenderSuccess
+	}
+
+	if title, ok := p.attachmentTitle(href); ok {
+		if text == "" {
+			text = title
+		}
+		_, _ = fmt.Fprintf(w, "[%s](%s)", text, p.localPath(title))
+		return converter.RenderSuccess
+	}
+
+	if strings.Contains(href, "://") || strings.HasPrefix(href, "#") {
+		return converter.RenderTryNext
+	}
+
+	path, fragment, _ := strings.Cut(href, "#")
+	if matches := exportPageRef.FindStringSubmatch(path); len(matches) > 1 {
+		link := "confluence://pageId/" + matches[1]
+		if fragment != "" {
+			link += "#" + fragment
+		}
+		_, _ = fmt.Fprintf(w, "[%s](%s)", text, link)
+		return converter.RenderSuccess
+	}
+
+	return converter.RenderTryNext
+}
+
+// attachmentTitle maps an export-relative attachment path to the page attachment title
+func (p *ViewPlugin) attachmentTitle(src string) (string, bool) {
+	if p.currentPage == nil || src == "" {
+		return "", false
+	}
+
+	src, _, _ = strings.Cut(src, "?")
+	if unescaped, err := url.PathUnescape(src); err == nil {
+		src = unescaped
+	}
+
+	for _, attachment := range p.currentPage.Attachments {
+		if attachment.DownloadLink == src {
+			if !slices.Contains(p.referenced, attachment.Title) {
+				p.referenced = append(p.referenced, attachment.Title)
+			}
+			return attachment.Title, true
+		}
+	}
+	return "", false
+}
+
+func (p *ViewPlugin) localPath(title string) string {
+	return url.PathEscape(p.imageFolder + "/" + title)
+}
+
+// handleTaskList renders an inline task list as a GitHub task list, matching the
+// storage format output
+func (p *ViewPlugin) handleTaskList(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if !hasClass(n, "inline-task-list") {
+		return converter.RenderTryNext
+	}
+
+	var buf strings.Builder
+	writeTasks(ctx, &buf, parseViewTaskList(n), "")
+	if buf.Len() == 0 {
+		return converter.RenderSuccess
+	}
+	_, _ = w.WriteString("\n\n" + strings.TrimSuffix(buf.String(), "\n") + "\n\n")
+	return converter.RenderSuccess
+}
+
+// parseViewTaskList reads the items of an inline task list. Nested lists are
+// the subtasks of the item they sit in.
+func parseViewTaskList(list *html.Node) []task {
+	var tasks []task
+	for item := list.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.Data != "li" {
+			continue
+		}
+		t := task{done: hasClass(item, "checked")}
+		for child := item.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && hasClass(child, "inline-task-list") {
+				t.subtasks = append(t.subtasks, parseViewTaskList(child)...)
+				continue
+			}
+			t.content = append(t.content, child)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+// collectTasks records the items of every inline task list in document order
+func (p *ViewPlugin) collectTasks(_ converter.Context, doc *html.Node) {
+	p.tasks = nil
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "li" && n.Parent != nil && hasClass(n.Parent, "inline-task-list") {
+			if summary := summarizeViewTask(n); summary.Text != "" {
+				p.tasks = append(p.tasks, summary)
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+}
+
+// summarizeViewTask reads a rendered task's plain text. The first mentioned user
+// is its assignee and the first date its due date, as for storage-format tasks.
+func summarizeViewTask(item *html.Node) mdmodel.Task {
+	summary := mdmodel.Task{Status: taskCheckbox(hasClass(item, "checked"), "complete", "incomplete")}
+
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			text.WriteString(n.Data)
+			return
+		case n.Type != html.ElementNode:
+			return
+		}
+		switch {
+		case (n.Data == "ul" || n.Data == "ol") && n != item:
+			// Nested task lists are collected on their own
+			return
+		case n.Data == "a" && hasClass(n, "user-mention"):
+			name := strings.TrimPrefix(strings.TrimSpace(textContent(n)), "@")
+			if summary.Assignee == "" {
+				summary.Assignee = name
+			}
+			text.WriteString(" @" + name + " ")
+			return
+		case n.Data == "time":
+			due := attrValue(n, "datetime")
+			if summary.Due == "" {
+				summary.Due = due
+			}
+			text.WriteString(" " + due + " ")
+			return
+		case n.Data == "p" || n.Data == "br":
+			text.WriteString(" ")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(item)
+	summary.Text = strings.Join(strings.Fields(text.String()), " ")
+	return summary
+}
+
+// renderChildren converts the children of a node to trimmed Markdown
+func renderChildren(ctx converter.Context, n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+
+	var buf strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		ctx.RenderNodes(ctx, &buf, child)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// attrValue returns the value of an attribute, or "" when absent
+func attrValue(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// hasClass reports whether an element carries the given CSS class
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attrValue(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// findByClass finds the first descendant element carrying the given CSS class
+func findByClass(n *html.Node, class string) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && hasClass(child, class) {
+			return child
+		}
+		if found := findByClass(child, class); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findElement finds the first descendant element with the given tag name
+func findElement(n *html.Node, tag string) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == tag {
+			return child
+		}
+		if found := findElement(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent concatenates all text beneath a node
+func textContent(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
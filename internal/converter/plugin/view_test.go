@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	mdmodel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+func TestViewPluginConvert(t *testing.T) {
+	view := NewViewPlugin("assets")
+	view.SetCurrentPage(&model.ConfluencePage{
+		ID: "1",
+		Attachments: []model.ConfluenceAttachment{
+			{ID: "2", Title: "diagram one.png", DownloadLink: "attachments/1/2.png"},
+		},
+	})
+	conv := convpkg.NewConverter(convpkg.WithPlugins(
+		base.NewBasePlugin(),
+		commonmark.NewCommonmarkPlugin(),
+		NewConfluencePlugin(nil, "assets"),
+		view,
+	))
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "information macro",
+			html: `<div class="confluence-information-macro confluence-information-macro-warning"><span class="aui-icon"></span><div class="confluence-information-macro-body"><p>Careful</p></div></div>`,
+			want: "> ⚠️ **Warning:** Careful",
+		},
+		{
+			name: "status lozenge",
+			html: `<p><span class="status-macro aui-lozenge aui-lozenge-success">DONE</span></p>`,
+			want: "🟢 **DONE**",
+		},
+		{
+			name: "code panel",
+			html: `<div class="code panel pdl"><div class="codeContent panelContent pdl"><pre class="syntaxhighlighter-pre" data-syntaxhighlighter-params="brush: go; gutter: false">fmt.Println(1)</pre></div></div>`,
+			want: "```go\nfmt.Println(1)\n```",
+		},
+		{
+			name: "titled collapsed code panel",
+			html: `<div class="code panel pdl"><div class="codeHeader panelHeader pdl"><b>build.sh</b></div><div class="codeContent panelContent pdl"><pre class="syntaxhighlighter-pre" data-syntaxhighlighter-params="brush: shell; gutter: true; first-line: 3; collapse: true">make</pre></div></div>`,
+			want: "<details>\n<summary>build.sh</summary>\n\n```bash\nmake\n```\n\n</details>",
+		},
+		{
+			name: "titled panel",
+			html: `<div class="panel" style="border-width: 1px;"><div class="panelHeader" style="border-bottom-width: 1px;"><b>Checklist</b></div><div class="panelContent"><p>One</p><p>Two</p></div></div>`,
+			want: "> **Checklist**\n>\n> One\n>\n> Two",
+		},
+		{
+			name: "attachment image",
+			html: `<p><img class="confluence-embedded-image" src="attachments/1/2.png?width=300"></p>`,
+			want: "![diagram one.png](assets%2Fdiagram%20one.png)",
+		},
+		{
+			name: "page link",
+			html: `<p><a href="Other-Page_42.html#Other-Page-Intro">Other</a></p>`,
+			want: "[Other](confluence://pageId/42#Other-Page-Intro)",
+		},
+		{
+			name: "user link",
+			html: `<p>Ask<a class="confluence-userlink user-mention" data-username="ada">Ada Lovelace</a>now</p>`,
+			want: "Ask @Ada Lovelace now",
+		},
+		{
+			name: "task list",
+			html: `<ul class="inline-task-list" data-inline-tasks-content-id="7"><li data-inline-task-id="1"><span>Ship it</span><ul class="inline-task-list"><li class="checked" data-inline-task-id="2"><span>Tag <strong>build</strong></span></li></ul></li><li data-inline-task-id="3"></li></ul><p>After</p>`,
+			want: "- [ ] Ship it\n  - [x] Tag **build**\n\nAfter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conv.ConvertString(tt.html)
+			if err != nil {
+				t.Fatalf("convert error: %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	if refs := view.ReferencedAttachments(); len(refs) != 1 || refs[0] != "diagram one.png" {
+		t.Fatalf("unexpected referenced attachments: %#v", refs)
+	}
+}
+
+func TestViewPluginTasks(t *testing.T) {
+	view := NewViewPlugin("assets")
+	conv := convpkg.NewConverter(convpkg.WithPlugins(base.NewBasePlugin(), commonmark.NewCommonmarkPlugin(), view))
+
+	html := `<ul class="inline-task-list" data-inline-tasks-content-id="7">` +
+		`<li data-inline-task-id="1"><span>Ship <a class="confluence-userlink user-mention" data-account-id="557058:jane">Jane Doe</a> by <time datetime="2024-01-02" class="date-past">02 Jan 2024</time></span>` +
+		`<ul class="inline-task-list"><li class="checked" data-inline-task-id="2"><span>Tag build</span></li></ul></li>` +
+		`<li data-inline-task-id="3"></li>` +
+		`</ul><ul><li>Not a task</li></ul>`
+	if _, err := conv.ConvertString(html); err != nil {
+		t.Fatalf("convert error: %v", err)
+	}
+
+	want := []mdmodel.Task{
+		{Status: "incomplete", Text: "Ship @Jane Doe by 2024-01-02", Assignee: "Jane Doe", Due: "2024-01-02"},
+		{Status: "complete", Text: "Tag build"},
+	}
+	if got := view.Tasks(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tasks:\n got: %+v\nwant: %+v", got, want)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/adf"
 	"github.com/jackchuka/confluence-md/internal/converter/model"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin"
 )
@@ -22,6 +24,38 @@ func (c *Converter) convertHtml(html string) (string, error) {
 	return c.postprocessMarkdown(md), nil
 }
 
+// convertView converts rendered Confluence view HTML into Markdown text.
+func (c *Converter) convertView(html string) (string, error) {
+	md, err := c.viewConverter.ConvertString(html)
+	if err != nil {
+		return "", err
+	}
+
+	return c.postprocessMarkdown(md), nil
+}
+
+// convertADF converts an ADF JSON document into Markdown text, returning the renderer
+// for the referenced image filenames and the excerpt.
+func (c *Converter) convertADF(body string, page *confluenceModel.ConfluencePage) (string, *adf.Renderer, error) {
+	doc, err := adf.Parse(body)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var pageAttachments []confluenceModel.ConfluenceAttachment
+	if page != nil {
+		pageAttachments = page.Attachments
+	}
+	renderer := adf.NewRenderer(c.imageFolder, pageAttachments)
+	if c.callouts != nil {
+		renderer.SetCallouts(c.callouts)
+	}
+	renderer.SetLayoutStyle(c.layoutStyle)
+	md := renderer.Render(doc)
+
+	return c.postprocessMarkdown(md), renderer, nil
+}
+
 // postprocessMarkdown normalizes whitespace and link formatting in Markdown output.
 func (c *Converter) postprocessMarkdown(markdown string) string {
 	markdown = regexp.MustCompile(`\n{3,}`).ReplaceAllString(markdown, "\n\n")
@@ -33,17 +67,25 @@ func (c *Converter) postprocessMarkdown(markdown string) string {
 
 // extractImageReferences finds image attachments referenced in the Confluence HTML.
 func (c *Converter) extractImageReferences(html, pageID, baseURL string) []model.ImageRef {
-	var imageRefs []model.ImageRef
-
 	acImageRegex := regexp.MustCompile(`<ac:image[^>]*>[\s\S]*?</ac:image>`)
 	matches := acImageRegex.FindAllString(html, -1)
 
+	var fileNames []string
 	for _, imageHTML := range matches {
 		fileName := plugin.ParseConfluenceImage(imageHTML)
 		if fileName == "" {
 			continue
 		}
+		fileNames = append(fileNames, fileName)
+	}
+
+	return buildImageRefs(fileNames, pageID, baseURL)
+}
 
+// buildImageRefs builds download references for attachment filenames on a page.
+func buildImageRefs(fileNames []string, pageID, baseURL string) []model.ImageRef {
+	var imageRefs []model.ImageRef
+	for _, fileName := range fileNames {
 		encodedFilename := url.QueryEscape(fileName)
 		actualURL := fmt.Sprintf("%s/wiki/download/attachments/%s/%s",
 			strings.TrimSuffix(baseURL, "/"), pageID, encodedFilename)
@@ -59,8 +101,8 @@ func (c *Converter) extractImageReferences(html, pageID, baseURL string) []model
 
 // fixMarkdownLinks converts Confluence-specific links into internal references.
 func fixMarkdownLinks(markdown string) string {
-	confLinkRegex := regexp.MustCompile(`\[([^\]]+)\]\(/wiki/spaces/([^/]+)/pages/(\d+)/[^)]+\)`)
-	return confLinkRegex.ReplaceAllString(markdown, "[$1](confluence://pageId/$3)")
+	confLinkRegex := regexp.MustCompile(`\[([^\]]+)\]\(/wiki/spaces/([^/]+)/pages/(\d+)(?:/[^)#]*)?(#[^)]*)?\)`)
+	return confLinkRegex.ReplaceAllString(markdown, "[$1](confluence://pageId/$3$4)")
 }
 
 // fixNestedListSpacing removes extraneous blank lines in nested lists.
@@ -75,16 +117,27 @@ func fixNestedListSpacing(markdown string) string {
 }
 
 // preprocessCDATA preserves content inside CDATA nodes prior to HTML parsing.
+// Plain-text link bodies are inlined as text, since a <pre> would break the link out of its paragraph.
 func (c *Converter) preprocessCDATA(html string) string {
+	html = linkBodyCDATARegex.ReplaceAllStringFunc(html, func(match string) string {
+		submatch := linkBodyCDATARegex.FindStringSubmatch(match)
+		return submatch[1] + escapeHTMLText(submatch[2]) + submatch[3]
+	})
+
 	cdataRegex := regexp.MustCompile(`<!\[CDATA\[([\s\S]*?)\]\]>`)
 	return cdataRegex.ReplaceAllStringFunc(html, func(match string) string {
 		if submatch := cdataRegex.FindStringSubmatch(match); len(submatch) > 1 {
-			content := submatch[1]
-			content = strings.ReplaceAll(content, "&", "&amp;")
-			content = strings.ReplaceAll(content, "<", "&lt;")
-			content = strings.ReplaceAll(content, ">", "&gt;")
-			return fmt.Sprintf("<pre data-cdata='true'>%s</pre>", content)
+			return fmt.Sprintf("<pre data-cdata='true'>%s</pre>", escapeHTMLText(submatch[1]))
 		}
 		return match
 	})
 }
+
+var linkBodyCDATARegex = regexp.MustCompile(`(<ac:plain-text-link-body>)\s*<!\[CDATA\[([\s\S]*?)\]\]>\s*(</ac:plain-text-link-body>)`)
+
+// escapeHTMLText escapes text so it parses back to the same characters
+func escapeHTMLText(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	return strings.ReplaceAll(text, ">", "&gt;")
+}
@@ -0,0 +1,183 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// TaskGrouping controls how tasks are sectioned in the Markdown task report
+type TaskGrouping string
+
+const (
+	TaskGroupAssignee TaskGrouping = "assignee" // A section per assignee, unassigned tasks last
+	TaskGroupPage     TaskGrouping = "page"     // A section per page, in conversion order
+)
+
+// ParseTaskGrouping validates a task grouping name
+func ParseTaskGrouping(name string) (TaskGrouping, error) {
+	switch grouping := TaskGrouping(strings.ToLower(name)); grouping {
+	case TaskGroupAssignee, TaskGroupPage:
+		return grouping, nil
+	default:
+		return "", fmt.Errorf("unsupported task grouping %q (expected assignee or page)", name)
+	}
+}
+
+// unassigned is the section heading for tasks that mention nobody
+const unassigned = "Unassigned"
+
+var taskLinkEscaper = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+// TaskEntry is a task and the converted page it was found on
+type TaskEntry struct {
+	model.Task
+	PageID    string `json:"pageId"`
+	PageTitle string `json:"pageTitle"`
+	Path      string `json:"path"`
+}
+
+// TaskReport collects the tasks of converted pages into one Markdown and JSON report
+type TaskReport struct {
+	entries  []TaskEntry
+	grouping TaskGrouping
+}
+
+// NewTaskReport creates an empty report sectioned by grouping
+func NewTaskReport(grouping TaskGrouping) *TaskReport {
+	return &TaskReport{grouping: grouping}
+}
+
+// Add records the tasks of a page converted to outputPath
+func (r *TaskReport) Add(pageID, title, outputPath string, tasks []model.Task) {
+	for _, task := range tasks {
+		r.entries = append(r.entries, TaskEntry{Task: task, PageID: pageID, PageTitle: title, Path: outputPath})
+	}
+}
+
+// Write writes the report as Markdown and as JSON. Page paths in both are
+// relative to the report.
+func (r *TaskReport) Write(markdownPath, jsonPath string) error {
+	if err := os.WriteFile(markdownPath, []byte(r.Markdown(markdownPath)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", markdownPath, err)
+	}
+
+	data, err := r.JSON(jsonPath)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", jsonPath, err)
+	}
+	return nil
+}
+
+// JSON encodes every task with its page path relative to reportPath
+func (r *TaskReport) JSON(reportPath string) ([]byte, error) {
+	entries := make([]TaskEntry, len(r.entries))
+	for n, entry := range r.entries {
+		if rel, err := filepath.Rel(filepath.Dir(reportPath), entry.Path); err == nil {
+			entry.Path = filepath.ToSlash(rel)
+		}
+		entries[n] = entry
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode task report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Markdown renders the report as task lists sectioned by the report's grouping,
+// linking pages relative to reportPath
+func (r *TaskReport) Markdown(reportPath string) string {
+	var b strings.Builder
+	b.WriteString("# Tasks\n\n")
+
+	open, pages := 0, map[string]bool{}
+	for _, entry := range r.entries {
+		if !entry.Done() {
+			open++
+		}
+		pages[entry.PageID] = true
+	}
+	if len(r.entries) == 0 {
+		b.WriteString("No tasks found.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d open and %d completed tasks on %d pages.\n", open, len(r.entries)-open, len(pages))
+
+	for _, section := range r.sections() {
+		b.WriteString("\n## ")
+		if r.grouping == TaskGroupPage {
+			entry := section[0]
+			fmt.Fprintf(&b, "[%s](%s)\n\n", taskLinkEscaper.Replace(entry.PageTitle), relativeLink(reportPath, entry.Path, ""))
+		} else {
+			b.WriteString(assigneeOf(section[0]) + "\n\n")
+		}
+
+		for _, entry := range section {
+			box := "[ ]"
+			if entry.Done() {
+				box = "[x]"
+			}
+			fmt.Fprintf(&b, "- %s %s", box, entry.Text)
+			if r.grouping != TaskGroupPage {
+				fmt.Fprintf(&b, " ([%s](%s))", taskLinkEscaper.Replace(entry.PageTitle), relativeLink(reportPath, entry.Path, ""))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// sections splits the entries by page in conversion order, or by assignee
+// name with unassigned tasks last
+func (r *TaskReport) sections() [][]TaskEntry {
+	key := assigneeOf
+	if r.grouping == TaskGroupPage {
+		key = func(entry TaskEntry) string { return entry.PageID }
+	}
+
+	var keys []string
+	groups := make(map[string][]TaskEntry)
+	for _, entry := range r.entries {
+		k := key(entry)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], entry)
+	}
+
+	if r.grouping != TaskGroupPage {
+		slices.SortFunc(keys, func(a, b string) int {
+			switch {
+			case a == unassigned:
+				return 1
+			case b == unassigned:
+				return -1
+			default:
+				return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+			}
+		})
+	}
+
+	sections := make([][]TaskEntry, len(keys))
+	for n, k := range keys {
+		sections[n] = groups[k]
+	}
+	return sections
+}
+
+func assigneeOf(entry TaskEntry) string {
+	if entry.Assignee == "" {
+		return unassigned
+	}
+	return entry.Assignee
+}
@@ -0,0 +1,114 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+func newTestTaskReport(grouping TaskGrouping) *TaskReport {
+	report := NewTaskReport(grouping)
+	report.Add("1", "Weekly [Ops] sync", filepath.Join("out", "meetings", "weekly ops sync.md"), []model.Task{
+		{Status: "incomplete", Text: "Rotate keys @Jane Doe 2024-01-02", Assignee: "Jane Doe", Due: "2024-01-02"},
+		{Status: "complete", Text: "Book room"},
+	})
+	report.Add("2", "Empty", filepath.Join("out", "empty.md"), nil)
+	report.Add("3", "Retro", filepath.Join("out", "retro.md"), []model.Task{
+		{Status: "incomplete", Text: "Fix alerts @alex", Assignee: "alex"},
+	})
+	return report
+}
+
+func TestTaskReportMarkdown(t *testing.T) {
+	reportPath := filepath.Join("out", "TODO.md")
+
+	tests := []struct {
+		name     string
+		grouping TaskGrouping
+		want     string
+	}{
+		{
+			name:     "by assignee",
+			grouping: TaskGroupAssignee,
+			want: "# Tasks\n\n2 open and 1 completed tasks on 2 pages.\n" +
+				"\n## alex\n\n- [ ] Fix alerts @alex ([Retro](retro.md))\n" +
+				"\n## Jane Doe\n\n- [ ] Rotate keys @Jane Doe 2024-01-02 ([Weekly \\[Ops\\] sync](meetings/weekly%20ops%20sync.md))\n" +
+				"\n## Unassigned\n\n- [x] Book room ([Weekly \\[Ops\\] sync](meetings/weekly%20ops%20sync.md))\n",
+		},
+		{
+			name:     "by page",
+			grouping: TaskGroupPage,
+			want: "# Tasks\n\n2 open and 1 completed tasks on 2 pages.\n" +
+				"\n## [Weekly \\[Ops\\] sync](meetings/weekly%20ops%20sync.md)\n\n- [ ] Rotate keys @Jane Doe 2024-01-02\n- [x] Book room\n" +
+				"\n## [Retro](retro.md)\n\n- [ ] Fix alerts @alex\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTestTaskReport(tt.grouping).Markdown(reportPath)
+			if got != tt.want {
+				t.Fatalf("unexpected markdown:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := NewTaskReport(TaskGroupPage).Markdown(reportPath); got != "# Tasks\n\nNo tasks found.\n" {
+		t.Fatalf("unexpected empty report: %q", got)
+	}
+}
+
+func TestTaskReportWrite(t *testing.T) {
+	dir := t.TempDir()
+	report := NewTaskReport(TaskGroupAssignee)
+	report.Add("1", "Sync", filepath.Join(dir, "team", "sync.md"), []model.Task{
+		{Status: "incomplete", Text: "Rotate keys @Jane Doe", Assignee: "Jane Doe", Due: "2024-01-02"},
+	})
+
+	markdownPath := filepath.Join(dir, "TODO.md")
+	jsonPath := filepath.Join(dir, "TODO.json")
+	if err := report.Write(markdownPath, jsonPath); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := os.Stat(markdownPath); err != nil {
+		t.Fatalf("markdown report missing: %v", err)
+	}
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("json report missing: %v", err)
+	}
+	var entries []map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	want := map[string]string{
+		"status":    "incomplete",
+		"text":      "Rotate keys @Jane Doe",
+		"assignee":  "Jane Doe",
+		"due":       "2024-01-02",
+		"pageId":    "1",
+		"pageTitle": "Sync",
+		"path":      "team/sync.md",
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	for key, value := range want {
+		if entries[0][key] != value {
+			t.Errorf("%s = %q, want %q", key, entries[0][key], value)
+		}
+	}
+}
+
+func TestParseTaskGrouping(t *testing.T) {
+	if grouping, err := ParseTaskGrouping("Page"); err != nil || grouping != TaskGroupPage {
+		t.Fatalf("ParseTaskGrouping(Page) = %q, %v", grouping, err)
+	}
+	if _, err := ParseTaskGrouping("due"); err == nil {
+		t.Fatal("expected error for unsupported grouping")
+	}
+}
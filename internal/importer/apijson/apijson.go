@@ -0,0 +1,87 @@
+// Package apijson reads Confluence REST API page responses saved to disk.
+package apijson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// Document is a page read from a saved API response
+type Document struct {
+	Path    string // File the page was read from
+	Page    *model.ConfluencePage
+	BaseURL string // Site URL from the response's _links.base, if present
+}
+
+// Load reads a saved page response, or every page response in a directory tree.
+// JSON files in a directory that are not page responses are skipped.
+func Load(path string) ([]Document, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		doc, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []Document{doc}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(file), ".json") {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+	}
+	sort.Strings(files)
+
+	var docs []Document
+	for _, file := range files {
+		doc, err := ReadFile(file)
+		if err != nil {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no Confluence page responses found in %s", path)
+	}
+	return docs, nil
+}
+
+// ReadFile reads a single saved page response
+func ReadFile(path string) (Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var apiPage model.ConfluenceAPIPage
+	if err := json.Unmarshal(data, &apiPage); err != nil {
+		return Document{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if apiPage.ID == "" || apiPage.Title == "" {
+		return Document{}, fmt.Errorf("%s is not a Confluence page response", path)
+	}
+
+	return Document{
+		Path:    path,
+		Page:    model.ConvertAPIPageToModel(&apiPage),
+		BaseURL: strings.TrimSuffix(strings.TrimSuffix(apiPage.Links.Base, "/"), "/wiki"),
+	}, nil
+}
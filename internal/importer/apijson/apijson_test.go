@@ -0,0 +1,53 @@
+package apijson
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadFile(t *testing.T) {
+	doc, err := ReadFile(filepath.Join("testdata", "page.json"))
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	page := doc.Page
+	if page.ID != "12345" || page.SpaceKey != "DOCS" || page.Version != 7 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if page.CreatedBy.DisplayName != "Ada Lovelace" || page.UpdatedBy.DisplayName != "Grace Hopper" {
+		t.Fatalf("unexpected users: %+v / %+v", page.CreatedBy, page.UpdatedBy)
+	}
+	if labels := page.GetLabelNames(); len(labels) != 1 || labels[0] != "release" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+	if len(page.Attachments) != 1 || page.Attachments[0].Title != "chart.png" {
+		t.Fatalf("unexpected attachments: %+v", page.Attachments)
+	}
+	if doc.BaseURL != "https://example.atlassian.net" {
+		t.Fatalf("unexpected base URL: %q", doc.BaseURL)
+	}
+	if err := page.Validate(); err != nil {
+		t.Fatalf("expected valid page: %v", err)
+	}
+}
+
+func TestReadFileRejectsNonPage(t *testing.T) {
+	if _, err := ReadFile(filepath.Join("testdata", "search.json")); err == nil {
+		t.Fatal("expected error for non-page response")
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	docs, err := Load("testdata")
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Page.ID != "12345" {
+		t.Fatalf("unexpected documents: %+v", docs)
+	}
+
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Fatal("expected error for directory without page responses")
+	}
+}
@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// offlineClient answers Client lookups from the pages of an export, so links
+// between pages resolve without API access
+type offlineClient struct {
+	root     string
+	pages    map[string]*model.ConfluencePage
+	children map[string][]*model.ConfluencePage
+	titles   map[model.ContentRef]*model.ConfluencePage
+	homes    map[string]*model.ConfluencePage // space key -> first top-level page
+}
+
+// Client returns a read-only Confluence client backed by the export's pages
+func (e *Export) Client() confluence.Client {
+	c := &offlineClient{
+		root:     e.Root,
+		pages:    make(map[string]*model.ConfluencePage),
+		children: make(map[string][]*model.ConfluencePage),
+		titles:   make(map[model.ContentRef]*model.ConfluencePage),
+		homes:    make(map[string]*model.ConfluencePage),
+	}
+
+	for _, node := range e.Pages {
+		if _, ok := c.homes[node.Page.SpaceKey]; !ok {
+			c.homes[node.Page.SpaceKey] = node.Page
+		}
+	}
+	_ = e.Walk(func(node *Node, _ []string) error {
+		page := node.Page
+		c.pages[page.ID] = page
+		ref := model.ContentRef{Type: model.ContentTypePage, SpaceKey: page.SpaceKey, Title: page.Title}
+		if _, ok := c.titles[ref]; !ok {
+			c.titles[ref] = page
+		}
+		for _, child := range node.Children {
+			c.children[page.ID] = append(c.children[page.ID], child.Page)
+		}
+		return nil
+	})
+
+	return c
+}
+
+func (c *offlineClient) GetPage(pageID string) (*model.ConfluencePage, error) {
+	page, ok := c.pages[pageID]
+	if !ok {
+		return nil, fmt.Errorf("page %s is not part of the export", pageID)
+	}
+	return page, nil
+}
+
+func (c *offlineClient) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
+	if _, ok := c.pages[pageID]; !ok {
+		return nil, fmt.Errorf("page %s is not part of the export", pageID)
+	}
+	return c.children[pageID], nil
+}
+
+func (c *offlineClient) DownloadAttachmentContent(attachment *model.ConfluenceAttachment) ([]byte, error) {
+	if attachment == nil {
+		return nil, fmt.Errorf("attachment is nil")
+	}
+	link, _, _ := strings.Cut(attachment.DownloadLink, "?")
+	path := filepath.Join(c.root, filepath.FromSlash(strings.TrimPrefix(link, "/")))
+	// Download links come from the export files, so they must not lead outside it
+	if rel, err := filepath.Rel(c.root, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("attachment %s is outside the export", attachment.Title)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %s: %w", attachment.Title, err)
+	}
+	return data, nil
+}
+
+func (c *offlineClient) GetUser(accountID string) (*model.ConfluenceUser, error) {
+	return nil, fmt.Errorf("user %s cannot be looked up offline", accountID)
+}
+
+func (c *offlineClient) FindContent(ref model.ContentRef) (*model.ConfluencePage, error) {
+	switch ref.Type {
+	case model.ContentTypeSpace:
+		return c.homes[ref.SpaceKey], nil
+	case model.ContentTypePage:
+		return c.titles[model.ContentRef{Type: ref.Type, SpaceKey: ref.SpaceKey, Title: ref.Title}], nil
+	default:
+		return nil, nil
+	}
+}
+
+func (c *offlineClient) SearchContent(cql string, limit int) ([]*model.ConfluencePage, error) {
+	return nil, fmt.Errorf("content cannot be searched offline")
+}
@@ -0,0 +1,155 @@
+// Package htmlexport reads Confluence HTML space exports for offline conversion.
+package htmlexport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/jackchuka/confluence-md/internal/importer"
+)
+
+const indexFile = "index.html"
+
+// Load reads an extracted HTML export found under dir.
+// When spaceKey is empty it is derived from the export's top-level directory name.
+func Load(dir, spaceKey string) (*importer.Export, error) {
+	root, err := importer.FindFile(dir, indexFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if spaceKey == "" {
+		spaceKey = filepath.Base(root)
+	}
+
+	pages, err := load(root, spaceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &importer.Export{Root: root, SpaceKey: spaceKey, Pages: pages}, nil
+}
+
+// load parses every page and rebuilds the hierarchy from index.html,
+// falling back to each page's breadcrumbs for pages missing from the index.
+func load(root, spaceKey string) ([]*importer.Node, error) {
+	files, err := filepath.Glob(filepath.Join(root, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export pages: %w", err)
+	}
+	sort.Strings(files)
+
+	nodes := make(map[string]*importer.Node) // file name -> node
+	breadcrumbs := make(map[string]string)   // file name -> parent file name
+	var order []string
+	for _, file := range files {
+		name := filepath.Base(file)
+		if name == indexFile {
+			continue
+		}
+
+		page, parent, err := parsePage(root, name, spaceKey)
+		if err != nil {
+			return nil, err
+		}
+		nodes[name] = &importer.Node{Page: page}
+		breadcrumbs[name] = parent
+		order = append(order, name)
+	}
+
+	placed := make(map[string]bool)
+	tree, err := parseIndex(filepath.Join(root, indexFile))
+	if err != nil {
+		return nil, err
+	}
+	pages := attachIndex(tree, nodes, placed)
+
+	for _, name := range order {
+		if placed[name] {
+			continue
+		}
+		node := nodes[name]
+		if parent, ok := nodes[breadcrumbs[name]]; ok && parent != node {
+			parent.Children = append(parent.Children, node)
+		} else {
+			pages = append(pages, node)
+		}
+		placed[name] = true
+	}
+
+	return pages, nil
+}
+
+// indexEntry is a page reference in the index.html page tree
+type indexEntry struct {
+	href     string
+	children []*indexEntry
+}
+
+// parseIndex reads the "Available Pages" tree from index.html
+func parseIndex(path string) ([]*indexEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", indexFile, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", indexFile, err)
+	}
+
+	var list *goquery.Selection
+	doc.Find(".pageSection").EachWithBreak(func(_ int, section *goquery.Selection) bool {
+		if strings.Contains(section.Find("h2").First().Text(), "Available Pages") {
+			list = section.ChildrenFiltered("ul").First()
+			return false
+		}
+		return true
+	})
+	if list == nil || list.Length() == 0 {
+		list = doc.Find("ul").First()
+	}
+
+	return parseIndexList(list), nil
+}
+
+func parseIndexList(list *goquery.Selection) []*indexEntry {
+	var entries []*indexEntry
+	list.ChildrenFiltered("li").Each(func(_ int, li *goquery.Selection) {
+		href, _ := li.ChildrenFiltered("a").First().Attr("href")
+		entry := &indexEntry{href: filepath.Base(href)}
+		li.ChildrenFiltered("ul").Each(func(_ int, nested *goquery.Selection) {
+			entry.children = append(entry.children, parseIndexList(nested)...)
+		})
+		entries = append(entries, entry)
+	})
+	return entries
+}
+
+// attachIndex links parsed pages following the index tree, skipping unknown entries
+func attachIndex(entries []*indexEntry, nodes map[string]*importer.Node, placed map[string]bool) []*importer.Node {
+	var result []*importer.Node
+	for _, entry := range entries {
+		children := attachIndex(entry.children, nodes, placed)
+		node, ok := nodes[entry.href]
+		if !ok || placed[entry.href] {
+			// Promote children of pages missing from the export
+			result = append(result, children...)
+			continue
+		}
+		node.Children = append(node.Children, children...)
+		placed[entry.href] = true
+		result = append(result, node)
+	}
+	return result
+}
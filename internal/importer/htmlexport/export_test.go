@@ -0,0 +1,132 @@
+package htmlexport
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/importer"
+)
+
+var testExport = map[string]string{
+	"DOCS/index.html": `<html><body><div class="pageSection"><h2>Available Pages:</h2><ul>
+		<li><a href="Home_1.html">Home</a><ul><li><a href="Guide_2.html">Guide</a></li></ul></li>
+	</ul></div></body></html>`,
+	"DOCS/Home_1.html": `<html><head><title>Docs : Home</title></head><body>
+		<div id="breadcrumbs"><a href="index.html">Docs</a></div>
+		<div class="page-metadata">Created by <span class="author">Ada</span>, last modified on Mar 04, 2024</div>
+		<div id="main-content"><p>Welcome</p><img src="attachments/1/10.png" data-linked-resource-default-alias="logo.png"></div>
+	</body></html>`,
+	"DOCS/Guide_2.html": `<html><head><title>Docs : Guide</title></head><body>
+		<div id="main-content"><p>Guide</p></div>
+	</body></html>`,
+	"DOCS/Orphan_3.html": `<html><head><title>Docs : Orphan</title></head><body>
+		<div id="breadcrumbs"><a href="index.html">Docs</a> <a href="Home_1.html">Home</a> <a href="Guide_2.html">Guide</a></div>
+		<div id="main-content"><p>Orphan</p><a href="attachments/3/missing.pdf">missing</a></div>
+	</body></html>`,
+	"DOCS/attachments/1/10.png": "png",
+}
+
+func writeExport(t *testing.T, dir string) {
+	t.Helper()
+	for name, content := range testExport {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeExportZip(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w := zip.NewWriter(f)
+	for name, content := range testExport {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeExport(t, dir)
+	zipPath := filepath.Join(t.TempDir(), "export.zip")
+	writeExportZip(t, zipPath)
+
+	for name, path := range map[string]string{"directory": dir, "zip": zipPath} {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup, err := importer.Unpack(path)
+			if err != nil {
+				t.Fatalf("unpack error: %v", err)
+			}
+			defer func() {
+				_ = cleanup()
+			}()
+
+			export, err := Load(dir, "")
+			if err != nil {
+				t.Fatalf("load error: %v", err)
+			}
+
+			if export.SpaceKey != "DOCS" {
+				t.Fatalf("unexpected space key: %q", export.SpaceKey)
+			}
+
+			var paths []string
+			_ = export.Walk(func(node *importer.Node, path []string) error {
+				paths = append(paths, filepath.Join(path...))
+				return nil
+			})
+			want := []string{"Home", filepath.Join("Home", "Guide"), filepath.Join("Home", "Guide", "Orphan")}
+			if len(paths) != len(want) {
+				t.Fatalf("unexpected pages: %v", paths)
+			}
+			for i := range want {
+				if paths[i] != want[i] {
+					t.Fatalf("unexpected pages: %v, want %v", paths, want)
+				}
+			}
+
+			home := export.Pages[0].Page
+			if home.ID != "1" || home.CreatedBy.DisplayName != "Ada" || home.UpdatedAt.Format("2006-01-02") != "2024-03-04" {
+				t.Fatalf("unexpected page metadata: %+v", home)
+			}
+			if len(home.Attachments) != 1 || home.Attachments[0].Title != "logo.png" || home.Attachments[0].DownloadLink != "attachments/1/10.png" {
+				t.Fatalf("unexpected attachments: %+v", home.Attachments)
+			}
+			if err := home.Validate(); err != nil {
+				t.Fatalf("expected valid page: %v", err)
+			}
+
+			orphan := export.Pages[0].Children[0].Children[0].Page
+			if len(orphan.Attachments) != 0 {
+				t.Fatalf("expected missing attachment to be skipped: %+v", orphan.Attachments)
+			}
+		})
+	}
+}
+
+func TestLoadWithoutIndex(t *testing.T) {
+	if _, err := Load(t.TempDir(), ""); err == nil {
+		t.Fatal("expected error for directory without index.html")
+	}
+}
@@ -0,0 +1,168 @@
+package htmlexport
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+var (
+	pageIDRegex       = regexp.MustCompile(`(?:^|_)(\d+)\.html$`)
+	modifiedDateRegex = regexp.MustCompile(`\bon\s+([A-Z][a-z]{2} \d{1,2}, \d{4})`)
+)
+
+// parsePage reads an exported page file and returns it with its breadcrumb parent file name
+func parsePage(root, name, spaceKey string) (*model.ConfluencePage, string, error) {
+	f, err := os.Open(filepath.Join(root, name))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read page %s: %w", name, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse page %s: %w", name, err)
+	}
+
+	content := doc.Find("#main-content").First()
+	if content.Length() == 0 {
+		content = doc.Find("body").First()
+	}
+	body, err := content.Html()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read content of %s: %w", name, err)
+	}
+
+	page := &model.ConfluencePage{
+		ID:       pageID(name),
+		Title:    pageTitle(doc, name),
+		SpaceKey: spaceKey,
+		Content: model.ConfluenceContent{
+			Storage: model.ContentStorage{
+				Value:          body,
+				Representation: model.RepresentationView,
+			},
+		},
+		Metadata: model.ConfluenceMetadata{
+			Properties: make(map[string]string),
+		},
+		Attachments: referencedAttachments(root, content),
+	}
+
+	metadata := doc.Find(".page-metadata").First()
+	page.CreatedBy.DisplayName = strings.TrimSpace(metadata.Find(".author").First().Text())
+	page.UpdatedBy.DisplayName = strings.TrimSpace(metadata.Find(".editor").First().Text())
+	if page.UpdatedBy.DisplayName == "" {
+		page.UpdatedBy.DisplayName = page.CreatedBy.DisplayName
+	}
+	if matches := modifiedDateRegex.FindStringSubmatch(metadata.Text()); len(matches) > 1 {
+		if modified, err := time.Parse("Jan 2, 2006", matches[1]); err == nil {
+			page.UpdatedAt = modified
+		}
+	}
+
+	// The last breadcrumb pointing at another page is the parent
+	parent := ""
+	doc.Find("#breadcrumbs a").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		if href = filepath.Base(href); href != indexFile && href != name {
+			parent = href
+		}
+	})
+
+	return page, parent, nil
+}
+
+// pageID extracts the page ID from an export file name such as Title_12345.html
+func pageID(name string) string {
+	if matches := pageIDRegex.FindStringSubmatch(name); len(matches) > 1 {
+		return matches[1]
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// pageTitle reads the page title, dropping the "Space Name : " prefix added by the export
+func pageTitle(doc *goquery.Document, name string) string {
+	title := strings.TrimSpace(doc.Find("#title-text").First().Text())
+	if title == "" {
+		title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+	if _, after, found := strings.Cut(title, " : "); found {
+		title = strings.TrimSpace(after)
+	}
+	if title == "" {
+		title = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return title
+}
+
+// referencedAttachments builds attachments for files under attachments/ that the content links to
+func referencedAttachments(root string, content *goquery.Selection) []model.ConfluenceAttachment {
+	var attachments []model.ConfluenceAttachment
+	seenLinks := make(map[string]bool)
+	seenTitles := make(map[string]bool)
+
+	add := func(link, alias string) {
+		link, _, _ = strings.Cut(link, "?")
+		if unescaped, err := url.PathUnescape(link); err == nil {
+			link = unescaped
+		}
+		if !strings.HasPrefix(link, "attachments/") || seenLinks[link] {
+			return
+		}
+
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(link)))
+		if err != nil || info.IsDir() || info.Size() == 0 {
+			return
+		}
+		seenLinks[link] = true
+
+		base := path.Base(link)
+		id := strings.TrimSuffix(base, path.Ext(base))
+		title := alias
+		if title == "" {
+			title = base
+		}
+		if seenTitles[strings.ToLower(title)] {
+			title = id + "-" + title
+		}
+		seenTitles[strings.ToLower(title)] = true
+
+		mediaType := mime.TypeByExtension(path.Ext(title))
+		if mediaType == "" {
+			mediaType = "application/octet-stream"
+		}
+
+		attachments = append(attachments, model.ConfluenceAttachment{
+			ID:           id,
+			Title:        title,
+			MediaType:    mediaType,
+			FileSize:     info.Size(),
+			DownloadLink: link,
+		})
+	}
+
+	content.Find("img[src]").Each(func(_ int, img *goquery.Selection) {
+		src, _ := img.Attr("src")
+		alias, _ := img.Attr("data-linked-resource-default-alias")
+		add(src, alias)
+	})
+	content.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		alias, _ := a.Attr("data-linked-resource-default-alias")
+		add(href, alias)
+	})
+
+	return attachments
+}
@@ -0,0 +1,157 @@
+// Package importer holds the pieces shared by the offline Confluence export readers.
+package importer
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// Export is a space read from an offline export
+type Export struct {
+	Root     string  // Directory attachment download links are relative to
+	SpaceKey string  // Space key assigned to every page
+	Pages    []*Node // Top-level pages in export order
+}
+
+// Node is a page in the export hierarchy
+type Node struct {
+	Page     *model.ConfluencePage
+	Children []*Node
+}
+
+// Walk visits every page depth-first with its ancestor titles
+func (e *Export) Walk(fn func(node *Node, path []string) error) error {
+	var walk func(nodes []*Node, parentPath []string) error
+	walk = func(nodes []*Node, parentPath []string) error {
+		for _, node := range nodes {
+			path := append(append([]string{}, parentPath...), node.Page.Title)
+			if err := fn(node, path); err != nil {
+				return err
+			}
+			if err := walk(node.Children, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(e.Pages, nil)
+}
+
+// Unpack returns a directory holding the export at path. Zip files are extracted
+// to a temporary directory which cleanup removes.
+func Unpack(path string) (dir string, cleanup func() error, err error) {
+	noop := func() error { return nil }
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to open export: %w", err)
+	}
+	if info.IsDir() {
+		return path, noop, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "confluence-export-*")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup = func() error {
+		return os.RemoveAll(tempDir)
+	}
+
+	if err := extractZip(path, tempDir); err != nil {
+		_ = cleanup()
+		return "", noop, err
+	}
+	return tempDir, cleanup, nil
+}
+
+// FindFile locates the shallowest directory under dir containing a file with the given name
+func FindFile(dir, name string) (string, error) {
+	var root string
+	depth := -1
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != name {
+			return nil
+		}
+		if level := strings.Count(path, string(filepath.Separator)); depth == -1 || level < depth {
+			root = filepath.Dir(path)
+			depth = level
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan export: %w", err)
+	}
+	if root == "" {
+		return "", fmt.Errorf("no %s found in export %s", name, dir)
+	}
+	return root, nil
+}
+
+// extractZip unpacks a zip archive into dir, rejecting entries that escape it
+func extractZip(path, dir string) error {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("failed to open zip export: %w", err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	for _, file := range reader.File {
+		target := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in zip export: %s", file.Name)
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			continue
+		}
+
+		if err := extractZipFile(file, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractZipFile(file *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s from zip export: %w", file.Name, err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer func() {
+		_ = dst.Close()
+	}()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+	}
+	return nil
+}
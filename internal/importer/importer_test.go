@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestUnpackRejectsEscapingEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	if _, err := w.Create("../outside.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	if _, _, err := Unpack(path); err == nil {
+		t.Fatal("expected error for entry escaping the export directory")
+	}
+}
+
+func TestFindFileShallowest(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"space/index.html", "space/nested/deeper/index.html"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root, err := FindFile(dir, "index.html")
+	if err != nil {
+		t.Fatalf("find error: %v", err)
+	}
+	if root != filepath.Join(dir, "space") {
+		t.Fatalf("unexpected root: %s", root)
+	}
+}
+
+func TestOfflineClientRejectsEscapingAttachments(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "export")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := (&Export{Root: root}).Client()
+	if data, err := client.DownloadAttachmentContent(&model.ConfluenceAttachment{Title: "secret.txt", DownloadLink: "/../secret.txt"}); err == nil {
+		t.Fatalf("expected error for link outside the export, read %q", data)
+	}
+}
@@ -0,0 +1,117 @@
+package xmlexport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// object is a persisted Hibernate entity from entities.xml, e.g.
+//
+//	<object class="Page" package="com.atlassian.confluence.pages">
+//	  <id name="id">123</id>
+//	  <property name="title"><![CDATA[Home]]></property>
+//	  <property name="parent" class="Page" package="..."><id name="id">100</id></property>
+//	</object>
+type object struct {
+	Class      string     `xml:"class,attr"`
+	ID         string     `xml:"id"`
+	Properties []property `xml:"property"`
+}
+
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+	Ref   string `xml:"id"` // Referenced entity ID for association properties
+}
+
+// prop returns the trimmed value of a scalar property
+func (o *object) prop(name string) string {
+	for _, p := range o.Properties {
+		if p.Name == name {
+			return strings.TrimSpace(p.Value)
+		}
+	}
+	return ""
+}
+
+// ref returns the ID of the entity an association property points to
+func (o *object) ref(name string) string {
+	for _, p := range o.Properties {
+		if p.Name == name {
+			return strings.TrimSpace(p.Ref)
+		}
+	}
+	return ""
+}
+
+// isCurrent reports whether the entity is the latest, live version of its content
+func (o *object) isCurrent() bool {
+	if o.ref("originalVersion") != "" {
+		return false
+	}
+	status := o.prop("contentStatus")
+	return status == "" || status == "current"
+}
+
+// entities indexes the objects of an entities.xml file by class and ID
+type entities struct {
+	byClass map[string][]*object
+	byID    map[string]*object // "<class>:<id>" -> object
+}
+
+// entityClasses are the object classes needed to rebuild pages
+var entityClasses = map[string]bool{
+	"Page":               true,
+	"BodyContent":        true,
+	"Space":              true,
+	"Attachment":         true,
+	"ContentProperty":    true,
+	"Label":              true,
+	"Labelling":          true,
+	"ConfluenceUserImpl": true,
+}
+
+// parseEntities streams entities.xml, keeping only the classes needed for conversion
+func parseEntities(r io.Reader) (*entities, error) {
+	e := &entities{
+		byClass: make(map[string][]*object),
+		byID:    make(map[string]*object),
+	}
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", EntitiesFile, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "object" {
+			continue
+		}
+
+		obj := &object{}
+		if err := decoder.DecodeElement(obj, &start); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", EntitiesFile, err)
+		}
+		if !entityClasses[obj.Class] {
+			continue
+		}
+
+		obj.ID = strings.TrimSpace(obj.ID)
+		e.byClass[obj.Class] = append(e.byClass[obj.Class], obj)
+		e.byID[obj.Class+":"+obj.ID] = obj
+	}
+
+	return e, nil
+}
+
+// get returns the object of the given class and ID, or nil
+func (e *entities) get(class, id string) *object {
+	return e.byID[class+":"+id]
+}
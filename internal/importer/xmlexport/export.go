@@ -0,0 +1,254 @@
+// Package xmlexport reads Confluence XML space backups (entities.xml) for offline conversion.
+package xmlexport
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/importer"
+)
+
+// EntitiesFile is the entity graph at the root of an XML space backup
+const EntitiesFile = "entities.xml"
+
+// storageBodyType is the BodyContent type of storage format XHTML
+const storageBodyType = "2"
+
+// timestampLayout is the format of entity date properties
+const timestampLayout = "2006-01-02 15:04:05.000"
+
+// Load reads an extracted XML backup found under dir.
+// When spaceKey is empty the key of the exported space is used.
+func Load(dir, spaceKey string) (*importer.Export, error) {
+	root, err := importer.FindFile(dir, EntitiesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(root, EntitiesFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", EntitiesFile, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	e, err := parseEntities(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if spaceKey == "" {
+		for _, space := range e.byClass["Space"] {
+			if spaceKey = space.prop("key"); spaceKey != "" {
+				break
+			}
+		}
+	}
+	if spaceKey == "" {
+		return nil, fmt.Errorf("no space key found in %s", EntitiesFile)
+	}
+
+	return &importer.Export{
+		Root:     root,
+		SpaceKey: spaceKey,
+		Pages:    buildTree(e, root, spaceKey),
+	}, nil
+}
+
+// buildTree converts the current pages and links them by their parent association
+func buildTree(e *entities, root, spaceKey string) []*importer.Node {
+	bodies := pageBodies(e)
+	unconverted := unconvertedBodies(e, bodies)
+	labels := pageLabels(e)
+	attachments := pageAttachments(e, root)
+
+	nodes := make(map[string]*importer.Node)
+	objects := make(map[string]*object)
+	var ids []string
+	for _, obj := range e.byClass["Page"] {
+		if !obj.isCurrent() {
+			continue
+		}
+		if unconverted[obj.ID] {
+			log.Printf("Page %q has no storage format body (e.g. only legacy wiki markup); converting it without content", obj.prop("title"))
+		}
+		nodes[obj.ID] = &importer.Node{Page: &model.ConfluencePage{
+			ID:       obj.ID,
+			Title:    obj.prop("title"),
+			SpaceKey: spaceKey,
+			Version:  atoi(obj.prop("version")),
+			Content: model.ConfluenceContent{
+				Storage: model.ContentStorage{
+					Value:          bodies[obj.ID],
+					Representation: model.RepresentationStorage,
+				},
+			},
+			Metadata: model.ConfluenceMetadata{
+				Labels:     labels[obj.ID],
+				Properties: make(map[string]string),
+			},
+			Attachments: attachments[obj.ID],
+			CreatedAt:   parseTimestamp(obj.prop("creationDate")),
+			UpdatedAt:   parseTimestamp(obj.prop("lastModificationDate")),
+			CreatedBy:   user(e, obj.ref("creator")),
+			UpdatedBy:   user(e, obj.ref("lastModifier")),
+		}}
+		objects[obj.ID] = obj
+		ids = append(ids, obj.ID)
+	}
+
+	// Siblings follow their manual ordering, then title
+	slices.SortFunc(ids, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(position(objects[a]), position(objects[b])),
+			cmp.Compare(nodes[a].Page.Title, nodes[b].Page.Title),
+		)
+	})
+
+	var pages []*importer.Node
+	for _, id := range ids {
+		node := nodes[id]
+		if parent, ok := nodes[objects[id].ref("parent")]; ok && parent != node {
+			parent.Children = append(parent.Children, node)
+		} else {
+			pages = append(pages, node)
+		}
+	}
+	return pages
+}
+
+// pageBodies maps content IDs to their storage format body. Bodies of other
+// types, such as legacy wiki markup, cannot be converted and are left out.
+func pageBodies(e *entities) map[string]string {
+	bodies := make(map[string]string)
+	for _, obj := range e.byClass["BodyContent"] {
+		if obj.prop("bodyType") != storageBodyType {
+			continue
+		}
+		if contentID := obj.ref("content"); bodies[contentID] == "" {
+			bodies[contentID] = obj.prop("body")
+		}
+	}
+	return bodies
+}
+
+// unconvertedBodies returns the IDs of content whose only bodies are in other
+// formats than storage format
+func unconvertedBodies(e *entities, bodies map[string]string) map[string]bool {
+	unconverted := make(map[string]bool)
+	for _, obj := range e.byClass["BodyContent"] {
+		if contentID := obj.ref("content"); obj.prop("bodyType") != storageBodyType && bodies[contentID] == "" && obj.prop("body") != "" {
+			unconverted[contentID] = true
+		}
+	}
+	return unconverted
+}
+
+// pageLabels maps content IDs to their global labels
+func pageLabels(e *entities) map[string][]model.Label {
+	labels := make(map[string][]model.Label)
+	for _, labelling := range e.byClass["Labelling"] {
+		label := e.get("Label", labelling.ref("label"))
+		if label == nil {
+			continue
+		}
+		if namespace := label.prop("namespace"); namespace != "" && namespace != "global" {
+			continue
+		}
+		contentID := labelling.ref("content")
+		labels[contentID] = append(labels[contentID], model.Label{ID: label.ID, Name: label.prop("name")})
+	}
+	return labels
+}
+
+// pageAttachments maps content IDs to the latest versions of their attachments.
+// Attachment data is stored at attachments/<contentID>/<attachmentID>/<version>.
+func pageAttachments(e *entities, root string) map[string][]model.ConfluenceAttachment {
+	properties := make(map[string]map[string]string) // attachment ID -> property name -> value
+	for _, obj := range e.byClass["ContentProperty"] {
+		contentID := obj.ref("content")
+		if properties[contentID] == nil {
+			properties[contentID] = make(map[string]string)
+		}
+		value := obj.prop("stringValue")
+		if value == "" {
+			value = obj.prop("longValue")
+		}
+		properties[contentID][obj.prop("name")] = value
+	}
+
+	attachments := make(map[string][]model.ConfluenceAttachment)
+	for _, obj := range e.byClass["Attachment"] {
+		if !obj.isCurrent() {
+			continue
+		}
+
+		contentID := obj.ref("containerContent")
+		if contentID == "" {
+			contentID = obj.ref("content")
+		}
+
+		version := obj.prop("version")
+		if version == "" {
+			version = "1"
+		}
+		link := path.Join("attachments", contentID, obj.ID, version)
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(link)))
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		title := obj.prop("title")
+		mediaType := cmp.Or(properties[obj.ID]["MEDIA_TYPE"], obj.prop("contentType"), mime.TypeByExtension(path.Ext(title)), "application/octet-stream")
+
+		attachments[contentID] = append(attachments[contentID], model.ConfluenceAttachment{
+			ID:           obj.ID,
+			Title:        title,
+			MediaType:    mediaType,
+			FileSize:     info.Size(),
+			DownloadLink: link,
+			Version:      atoi(version),
+		})
+	}
+	return attachments
+}
+
+// user resolves a user key to the exported user, falling back to the key itself
+func user(e *entities, key string) model.User {
+	if key == "" {
+		return model.User{}
+	}
+	name := key
+	if obj := e.get("ConfluenceUserImpl", key); obj != nil && obj.prop("name") != "" {
+		name = obj.prop("name")
+	}
+	return model.User{AccountID: key, DisplayName: name}
+}
+
+// position returns the manual sibling position of a page, ordering unpositioned pages last
+func position(obj *object) int {
+	if p, err := strconv.Atoi(obj.prop("position")); err == nil {
+		return p
+	}
+	return int(^uint(0) >> 1)
+}
+
+func parseTimestamp(value string) time.Time {
+	t, _ := time.Parse(timestampLayout, value)
+	return t
+}
+
+func atoi(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
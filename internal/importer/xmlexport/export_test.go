@@ -0,0 +1,169 @@
+package xmlexport
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/importer"
+)
+
+const testEntities = `<?xml version="1.0" encoding="UTF-8"?>
+<hibernate-generic datetime="2024-03-04 10:00:00">
+<object class="Space" package="com.atlassian.confluence.spaces">
+<id name="id">1</id>
+<property name="key"><![CDATA[DOCS]]></property>
+</object>
+<object class="ConfluenceUserImpl" package="com.atlassian.confluence.user">
+<id name="key"><![CDATA[u1]]></id>
+<property name="name"><![CDATA[ada]]></property>
+</object>
+<object class="Page" package="com.atlassian.confluence.pages">
+<id name="id">10</id>
+<property name="title"><![CDATA[Home]]></property>
+<property name="version">3</property>
+<property name="contentStatus"><![CDATA[current]]></property>
+<property name="creator" class="ConfluenceUserImpl" package="com.atlassian.confluence.user"><id name="key"><![CDATA[u1]]></id></property>
+<property name="lastModificationDate">2024-03-04 09:30:00.000</property>
+</object>
+<object class="Page" package="com.atlassian.confluence.pages">
+<id name="id">11</id>
+<property name="title"><![CDATA[Home]]></property>
+<property name="version">2</property>
+<property name="originalVersion" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+</object>
+<object class="Page" package="com.atlassian.confluence.pages">
+<id name="id">20</id>
+<property name="title"><![CDATA[Zeta]]></property>
+<property name="position">0</property>
+<property name="parent" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+</object>
+<object class="Page" package="com.atlassian.confluence.pages">
+<id name="id">21</id>
+<property name="title"><![CDATA[Alpha]]></property>
+<property name="position">1</property>
+<property name="parent" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+</object>
+<object class="Page" package="com.atlassian.confluence.pages">
+<id name="id">30</id>
+<property name="title"><![CDATA[Trashed]]></property>
+<property name="contentStatus"><![CDATA[deleted]]></property>
+</object>
+<object class="BodyContent" package="com.atlassian.confluence.core">
+<id name="id">100</id>
+<property name="body"><![CDATA[<p>Welcome <ac:image><ri:attachment ri:filename="logo.png" /></ac:image></p>]]></property>
+<property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+<property name="bodyType">2</property>
+</object>
+<object class="BodyContent" package="com.atlassian.confluence.core">
+<id name="id">101</id>
+<property name="body"><![CDATA[h1. Legacy *wiki* markup]]></property>
+<property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">21</id></property>
+<property name="bodyType">0</property>
+</object>
+<object class="BodyContent" package="com.atlassian.confluence.core">
+<id name="id">102</id>
+<property name="body"><![CDATA[h1. Old]]></property>
+<property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+<property name="bodyType">0</property>
+</object>
+<object class="Label" package="com.atlassian.confluence.labels">
+<id name="id">200</id>
+<property name="name"><![CDATA[howto]]></property>
+<property name="namespace"><![CDATA[global]]></property>
+</object>
+<object class="Label" package="com.atlassian.confluence.labels">
+<id name="id">201</id>
+<property name="name"><![CDATA[favourite]]></property>
+<property name="namespace"><![CDATA[my]]></property>
+</object>
+<object class="Labelling" package="com.atlassian.confluence.labels">
+<id name="id">300</id>
+<property name="label" class="Label" package="com.atlassian.confluence.labels"><id name="id">200</id></property>
+<property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+</object>
+<object class="Labelling" package="com.atlassian.confluence.labels">
+<id name="id">301</id>
+<property name="label" class="Label" package="com.atlassian.confluence.labels"><id name="id">201</id></property>
+<property name="content" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+</object>
+<object class="Attachment" package="com.atlassian.confluence.pages">
+<id name="id">400</id>
+<property name="title"><![CDATA[logo.png]]></property>
+<property name="version">2</property>
+<property name="containerContent" class="Page" package="com.atlassian.confluence.pages"><id name="id">10</id></property>
+</object>
+<object class="ContentProperty" package="com.atlassian.confluence.content">
+<id name="id">500</id>
+<property name="name"><![CDATA[MEDIA_TYPE]]></property>
+<property name="stringValue"><![CDATA[image/png]]></property>
+<property name="content" class="Attachment" package="com.atlassian.confluence.pages"><id name="id">400</id></property>
+</object>
+</hibernate-generic>
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"entities.xml":                testEntities,
+		"attachments/10/400/2":        "png",
+		"exportDescriptor.properties": "spaceKey=DOCS",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	export, err := Load(dir, "")
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if export.SpaceKey != "DOCS" {
+		t.Fatalf("unexpected space key: %q", export.SpaceKey)
+	}
+
+	var paths []string
+	_ = export.Walk(func(node *importer.Node, path []string) error {
+		paths = append(paths, filepath.Join(path...))
+		return nil
+	})
+	want := []string{"Home", filepath.Join("Home", "Zeta"), filepath.Join("Home", "Alpha")}
+	if len(paths) != len(want) {
+		t.Fatalf("unexpected pages: %v", paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("unexpected pages: %v, want %v", paths, want)
+		}
+	}
+
+	home := export.Pages[0].Page
+	if home.Version != 3 || home.CreatedBy.DisplayName != "ada" || home.UpdatedAt.Format("2006-01-02 15:04") != "2024-03-04 09:30" {
+		t.Fatalf("unexpected page metadata: %+v", home)
+	}
+	if labels := home.GetLabelNames(); len(labels) != 1 || labels[0] != "howto" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+	if len(home.Attachments) != 1 {
+		t.Fatalf("unexpected attachments: %+v", home.Attachments)
+	}
+	if attachment := home.Attachments[0]; attachment.MediaType != "image/png" || attachment.DownloadLink != "attachments/10/400/2" || attachment.FileSize != 3 {
+		t.Fatalf("unexpected attachment: %+v", attachment)
+	}
+	if err := home.Validate(); err != nil {
+		t.Fatalf("expected valid page: %v", err)
+	}
+	if body := home.Content.Storage.Value; body != `<p>Welcome <ac:image><ri:attachment ri:filename="logo.png" /></ac:image></p>` {
+		t.Fatalf("unexpected home body: %q", body)
+	}
+
+	// Wiki markup is not storage format, so a page with only a wiki markup body has no content
+	if alpha := export.Pages[0].Children[1].Page; alpha.Title != "Alpha" || alpha.Content.Storage.Value != "" {
+		t.Fatalf("unexpected wiki markup page: %q with body %q", alpha.Title, alpha.Content.Storage.Value)
+	}
+}
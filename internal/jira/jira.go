@@ -0,0 +1,213 @@
+// Package jira links and optionally enriches Jira issues referenced from Confluence pages
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/version"
+)
+
+// Issue holds the issue fields shown next to a Jira link
+type Issue struct {
+	Key     string
+	Summary string
+	Status  string
+}
+
+// Provider supplies issue data for Jira macros. GetIssue returns nil without an
+// error when the issue is unknown.
+type Provider interface {
+	GetIssue(key string) (*Issue, error)
+}
+
+// Config describes where Jira issues live and how to enrich them
+type Config struct {
+	BaseURL  string            // Jira site used when a macro's server is not mapped
+	Servers  map[string]string // Confluence application link server ID -> Jira base URL
+	Provider Provider          // Optional source of issue summaries and statuses
+}
+
+// SiteURL returns the Jira base URL for a macro's server ID, falling back to BaseURL
+func (c Config) SiteURL(serverID string) string {
+	if site, ok := c.Servers[serverID]; ok && serverID != "" {
+		return strings.TrimSuffix(site, "/")
+	}
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
+
+// IssueURL returns the browse URL for an issue
+func IssueURL(baseURL, key string) string {
+	return fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(key))
+}
+
+// SearchURL returns the issue navigator URL for a JQL query
+func SearchURL(baseURL, jql string) string {
+	return fmt.Sprintf("%s/issues/?jql=%s", strings.TrimSuffix(baseURL, "/"), url.QueryEscape(jql))
+}
+
+// apiIssue is the issue shape returned by the Jira REST API and its search results
+type apiIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+func (i apiIssue) issue() *Issue {
+	return &Issue{Key: i.Key, Summary: i.Fields.Summary, Status: i.Fields.Status.Name}
+}
+
+// errSiteUnavailable marks failures that affect every issue, such as an unreachable
+// site or rejected credentials
+var errSiteUnavailable = errors.New("jira site unavailable")
+
+// client fetches issues from the Jira REST API, remembering each issue it has looked up
+// and each failure, so an unreachable site is only waited for once
+type client struct {
+	baseURL    string
+	email      string
+	apiToken   string
+	httpClient *http.Client
+	userAgent  string
+
+	mu          sync.Mutex
+	issues      map[string]*Issue
+	failures    map[string]error
+	unavailable error // Set once the site fails for every issue
+}
+
+// NewClient creates a provider backed by the Jira REST API
+func NewClient(baseURL, email, apiToken string) Provider {
+	return &client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		email:    email,
+		apiToken: apiToken,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		userAgent: fmt.Sprintf("ConfluenceMd/%s", version.Short()),
+		issues:    make(map[string]*Issue),
+		failures:  make(map[string]error),
+	}
+}
+
+// GetIssue retrieves the summary and status of an issue
+func (c *client) GetIssue(key string) (*Issue, error) {
+	c.mu.Lock()
+	if c.unavailable != nil {
+		c.mu.Unlock()
+		return nil, c.unavailable
+	}
+	if err, ok := c.failures[key]; ok {
+		c.mu.Unlock()
+		return nil, err
+	}
+	issue, ok := c.issues[key]
+	c.mu.Unlock()
+	if ok {
+		return issue, nil
+	}
+
+	issue, err := c.fetchIssue(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case errors.Is(err, errSiteUnavailable):
+		if c.unavailable == nil {
+			c.unavailable = err
+			log.Printf("Jira issues are linked without summaries and statuses: %v", err)
+		}
+		return nil, err
+	case err != nil:
+		c.failures[key] = err
+		log.Printf("Failed to enrich Jira issue %s: %v", key, err)
+		return nil, err
+	}
+	c.issues[key] = issue
+	return issue, nil
+}
+
+func (c *client) fetchIssue(key string) (*Issue, error) {
+	params := url.Values{"fields": []string{"summary,status"}}
+	fullURL := fmt.Sprintf("%s/rest/api/2/issue/%s?%s", c.baseURL, url.PathEscape(key), params.Encode())
+
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiToken != "" {
+		req.SetBasicAuth(c.email, c.apiToken)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w: %w", key, errSiteUnavailable, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("failed to get issue %s: %w: HTTP %d", key, errSiteUnavailable, resp.StatusCode)
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get issue %s: HTTP %d - %s", key, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var issue apiIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode issue response: %w", err)
+	}
+	return issue.issue(), nil
+}
+
+// issueSet serves issues loaded ahead of time
+type issueSet map[string]*Issue
+
+func (s issueSet) GetIssue(key string) (*Issue, error) {
+	return s[key], nil
+}
+
+// LoadIssues reads a saved Jira search response (the JSON returned by
+// /rest/api/2/search) for offline enrichment
+func LoadIssues(path string) (Provider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issues file: %w", err)
+	}
+
+	var search struct {
+		Issues []apiIssue `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &search); err != nil {
+		return nil, fmt.Errorf("failed to parse issues file %s: %w", path, err)
+	}
+
+	issues := make(issueSet, len(search.Issues))
+	for _, issue := range search.Issues {
+		if issue.Key != "" {
+			issues[issue.Key] = issue.issue()
+		}
+	}
+	return issues, nil
+}
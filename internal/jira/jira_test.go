@@ -0,0 +1,124 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientGetIssue(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if user, token, ok := r.BasicAuth(); !ok || user != "user@example.com" || token != "token" {
+			t.Errorf("missing basic auth")
+		}
+		switch r.URL.Path {
+		case "/rest/api/2/issue/OPS-1":
+			if got := r.URL.Query().Get("fields"); got != "summary,status" {
+				t.Errorf("unexpected fields: %s", got)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"key":"OPS-1","fields":{"summary":"Rotate database credentials","status":{"name":"In Progress"}}}`))
+		case "/rest/api/2/issue/OPS-500":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL+"/", "user@example.com", "token")
+
+	issue, err := client.GetIssue("OPS-1")
+	if err != nil {
+		t.Fatalf("GetIssue returned error: %v", err)
+	}
+	if *issue != (Issue{Key: "OPS-1", Summary: "Rotate database credentials", Status: "In Progress"}) {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+
+	if issue, err := client.GetIssue("OPS-404"); err != nil || issue != nil {
+		t.Fatalf("expected unknown issue to resolve to nothing, got %+v, %v", issue, err)
+	}
+	if _, err := client.GetIssue("OPS-500"); err == nil {
+		t.Fatal("expected error for server failure")
+	}
+
+	// Found and missing issues and failures are remembered
+	_, _ = client.GetIssue("OPS-1")
+	_, _ = client.GetIssue("OPS-404")
+	if _, err := client.GetIssue("OPS-500"); err == nil {
+		t.Fatal("expected remembered error for server failure")
+	}
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestClientUnavailable(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, "user@example.com", "wrong")
+	for _, key := range []string{"OPS-1", "OPS-2", "DEV-3"} {
+		if _, err := client.GetIssue(key); err == nil {
+			t.Fatalf("expected error for %s", key)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected rejected credentials to stop further requests, got %d requests", got)
+	}
+
+	unreachable := NewClient("http://127.0.0.1:1", "", "")
+	_, first := unreachable.GetIssue("OPS-1")
+	_, second := unreachable.GetIssue("OPS-2")
+	if first == nil || second != first {
+		t.Fatalf("expected the connection failure to be reused, got %v and %v", first, second)
+	}
+}
+
+func TestLoadIssues(t *testing.T) {
+	provider, err := LoadIssues(filepath.Join("testdata", "search.json"))
+	if err != nil {
+		t.Fatalf("LoadIssues returned error: %v", err)
+	}
+
+	issue, _ := provider.GetIssue("OPS-2")
+	if issue == nil || issue.Summary != "Document rollback" || issue.Status != "Done" {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+	if issue, _ := provider.GetIssue("OPS-3"); issue != nil {
+		t.Fatalf("expected unknown issue, got %+v", issue)
+	}
+
+	if _, err := LoadIssues(filepath.Join("testdata", "missing.json")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestConfigURLs(t *testing.T) {
+	cfg := Config{
+		BaseURL: "https://example.atlassian.net/",
+		Servers: map[string]string{"abc-123": "https://jira.internal/"},
+	}
+
+	if got := cfg.SiteURL("abc-123"); got != "https://jira.internal" {
+		t.Errorf("SiteURL(mapped) = %q", got)
+	}
+	if got := cfg.SiteURL("other"); got != "https://example.atlassian.net" {
+		t.Errorf("SiteURL(unmapped) = %q", got)
+	}
+	if got := IssueURL("https://jira.internal", "OPS-1"); got != "https://jira.internal/browse/OPS-1" {
+		t.Errorf("IssueURL = %q", got)
+	}
+	if got := SearchURL("https://jira.internal", `project = OPS AND status = "To Do"`); got != "https://jira.internal/issues/?jql=project+%3D+OPS+AND+status+%3D+%22To+Do%22" {
+		t.Errorf("SearchURL = %q", got)
+	}
+}